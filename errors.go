@@ -0,0 +1,105 @@
+package gopartial
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldError describes a partial value that could not be assigned to a field
+type FieldError struct {
+	// Path is the path of the struct field, e.g. "Address.City"
+	Path string
+	// Key is the path of the partial key, e.g. "address.city"
+	Key string
+	// Expected is the Go type of the field, e.g. "null.String", empty for an unknown key
+	Expected string
+	// Received is the kind of the value, e.g. "float64", or "null"
+	Received string
+	// Reason explains why the value was rejected
+	Reason string
+	// Err is the error returned by the converter that rejected the value, if any
+	Err error
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	// unknown keys and conflicts don't expect any type
+	if e.Expected == "" {
+		return fmt.Sprintf("%v: %v (received %v)", e.Path, e.Reason, e.Received)
+	}
+	return fmt.Sprintf("%v: %v (expected %v, received %v)", e.Path, e.Reason, e.Expected, e.Received)
+}
+
+// Unwrap returns the error returned by the converter that rejected the value
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// PatchError is returned when some of the partial values could not be assigned,
+// it holds an error for each of them
+type PatchError struct {
+	Fields []FieldError
+}
+
+// Error implements the error interface
+func (e *PatchError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return fmt.Sprintf("Partial update failed for %d field(s): %v", len(e.Fields), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the fields, so that errors.Is and errors.As look into them
+func (e *PatchError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, field := range e.Fields {
+		errs[i] = field
+	}
+	return errs
+}
+
+const (
+	// reasonIncompatible is the reason of a value that no updater could assign
+	reasonIncompatible = "incompatible value"
+	// reasonNoElement is the reason of a slice or array index that doesn't exist
+	reasonNoElement = "no element at this index"
+	// reasonUnknownKey is the reason of a key that matches no field in strict mode
+	reasonUnknownKey = "unknown key"
+	// reasonUnknownConverter is the reason of a value of a field tagged with a converter that doesn't exist
+	reasonUnknownConverter = "unknown converter"
+)
+
+// newFieldError returns the error for the value v that could not be assigned to the
+// field of type fieldType at at
+func newFieldError(at location, fieldType reflect.Type, v reflect.Value, reason string) FieldError {
+	return FieldError{
+		Path:     at.path,
+		Key:      at.key,
+		Expected: fieldType.String(),
+		Received: received(v),
+		Reason:   reason,
+	}
+}
+
+// newConflictError returns the error for the value val of the dot-path key of conflict
+// within the object at at
+func newConflictError(at location, conflict *pathConflict, val interface{}) FieldError {
+	conflictAt := at.child(conflict.path, conflict.path)
+	return FieldError{
+		Path:     conflictAt.path,
+		Key:      conflictAt.key,
+		Received: received(reflect.ValueOf(val)),
+		Reason:   conflict.Error(),
+		Err:      conflict,
+	}
+}
+
+// received describes the kind of the value v, "null" for a null value
+func received(v reflect.Value) string {
+	if !v.IsValid() {
+		return "null"
+	}
+	return v.Kind().String()
+}
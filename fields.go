@@ -0,0 +1,286 @@
+package gopartial
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// field is a struct field that can be updated from a partial, including the fields
+// promoted from embedded structs
+type field struct {
+	reflect.StructField
+
+	// name is the partial key of the field, the tag name or the Go field name
+	name string
+	// tagged is true when the name comes from the struct tag
+	tagged bool
+	// quoted is true when the field is tagged with the ",string" option, its
+	// number or bool value is then encoded as a string
+	quoted bool
+	// converter is the name of the converter of the field, from the `partial:"conv=name"` tag
+	converter string
+	// index is the index sequence of the field from the parent struct
+	index []int
+	// embedded are the embedded struct fields the field is promoted from
+	embedded []reflect.StructField
+}
+
+// embeddedStruct is an embedded struct whose fields are promoted to the parent struct
+type embeddedStruct struct {
+	typ      reflect.Type
+	index    []int
+	embedded []reflect.StructField
+}
+
+// structFields returns the fields of the struct type t in declaration order, named after
+// their tag name or their Go field name when the tag has no name. Fields tagged "-" are
+// left out. Fields of
+// embedded structs are promoted to t following the encoding/json rules: a field shadows
+// the fields with the same name at a deeper level, and fields with the same name at the
+// same level are all dropped unless exactly one of them is tagged.
+func structFields(t reflect.Type, tagName string) []field {
+	fields := make([]field, 0, t.NumField())
+
+	// walk the embedded structs breadth first, level by level
+	current := []embeddedStruct{}
+	next := []embeddedStruct{{typ: t}}
+	// count and nextCount keep track of how many times a struct type is embedded on a level,
+	// its fields are ambiguous when it is embedded more than once
+	count := map[reflect.Type]int{}
+	nextCount := map[reflect.Type]int{t: 1}
+	visited := map[reflect.Type]bool{}
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, s := range current {
+			if visited[s.typ] {
+				continue
+			}
+			visited[s.typ] = true
+
+			for i := 0; i < s.typ.NumField(); i++ {
+				sf := s.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if sf.Anonymous {
+					// the exported fields of unexported embedded structs are still promoted
+					if sf.PkgPath != "" && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if sf.PkgPath != "" {
+					continue
+				}
+
+				index := make([]int, len(s.index)+1)
+				copy(index, s.index)
+				index[len(s.index)] = i
+
+				tag := sf.Tag.Get(tagName)
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					tagged := name != ""
+					if !tagged {
+						name = sf.Name
+					}
+
+					// the ",string" option only applies to numbers and bools
+					quoted := false
+					if opts.Contains("string") {
+						switch ft.Kind() {
+						case reflect.Bool,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64:
+							quoted = true
+						}
+					}
+
+					fields = append(fields, field{
+						StructField: sf,
+						name:        name,
+						tagged:      tagged,
+						quoted:      quoted,
+						converter:   tagOptions(sf.Tag.Get(partialTagName)).Value("conv"),
+						index:       index,
+						embedded:    s.embedded,
+					})
+					if count[s.typ] > 1 {
+						// the same struct is embedded twice on this level, add the field
+						// once more so that it is dropped as ambiguous
+						fields = append(fields, fields[len(fields)-1])
+					}
+					continue
+				}
+
+				// promote the fields of the untagged embedded struct on the next level
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					embedded := make([]reflect.StructField, len(s.embedded)+1)
+					copy(embedded, s.embedded)
+					embedded[len(s.embedded)] = sf
+					next = append(next, embeddedStruct{typ: ft, index: index, embedded: embedded})
+				}
+			}
+		}
+	}
+
+	// group the fields by name, the dominant field of each name comes first
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
+		}
+		if fields[i].tagged != fields[j].tagged {
+			return fields[i].tagged
+		}
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		// a field is ambiguous if another one with the same name is as shallow and as tagged
+		if j-i == 1 || len(fields[i].index) != len(fields[i+1].index) || fields[i].tagged != fields[i+1].tagged {
+			dominant = append(dominant, fields[i])
+		}
+		i = j
+	}
+	fields = dominant
+
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+
+	return fields
+}
+
+// match ranks how a partial key matches a field, lower is better
+type match int
+
+const (
+	// matchName is an exact match on the field name
+	matchName match = iota
+	// matchGoName is an exact match on the Go field name of a tagged field
+	matchGoName
+	// matchFoldName is a case-insensitive match on the field name
+	matchFoldName
+	// matchFoldGoName is a case-insensitive match on the Go field name of a tagged field
+	matchFoldGoName
+	// noMatch is no match at all
+	noMatch
+)
+
+// matchKeys returns the partial key matching each of fields, by position in fields.
+// Each key matches the field it matches best, and each field takes the best of
+// the keys that matched it. It returns nil when only exact matches are possible,
+// the field names are then the keys.
+func (o *options) matchKeys(fields []field, partial map[string]interface{}) map[int]string {
+	// only exact matches are possible without options
+	if !o.caseInsensitive && !o.fieldNameFallback {
+		return nil
+	}
+	keys := make(map[int]string, len(partial))
+
+	// go through the keys in order so that ties are broken the same way every time
+	sortedKeys := make([]string, 0, len(partial))
+	for key := range partial {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	matches := make(map[int]match, len(partial))
+	for _, key := range sortedKeys {
+		best, bestMatch := -1, noMatch
+		for i, field := range fields {
+			if m := o.match(field, key); m < bestMatch {
+				best, bestMatch = i, m
+			}
+		}
+		if best == -1 {
+			continue
+		}
+		if m, ok := matches[best]; !ok || bestMatch < m {
+			keys[best] = key
+			matches[best] = bestMatch
+		}
+	}
+
+	return keys
+}
+
+// match ranks how key matches field
+func (o *options) match(field field, key string) match {
+	switch {
+	case field.name == key:
+		return matchName
+	case o.fieldNameFallback && field.tagged && field.Name == key:
+		return matchGoName
+	case o.caseInsensitive && strings.EqualFold(field.name, key):
+		return matchFoldName
+	case o.caseInsensitive && o.fieldNameFallback && field.tagged && strings.EqualFold(field.Name, key):
+		return matchFoldGoName
+	}
+	return noMatch
+}
+
+// lessIndex reports whether the index sequence a comes before b in declaration order
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// fieldByIndex returns the field of the struct value v with the index sequence index,
+// allocating the nil embedded struct pointers on the way, and copying the others when
+// copyOnWrite is set. The allocated pointers are only set by the returned commit function,
+// once the value assigned to the field has been accepted, which is nil when there are none.
+// It returns false when the field
+// can't be set, e.g. when it's promoted from a nil unexported struct pointer.
+func fieldByIndex(v reflect.Value, index []int, copyOnWrite bool) (reflect.Value, func(), bool) {
+	var pointers, allocations []reflect.Value
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() || copyOnWrite {
+				if !v.CanSet() {
+					return reflect.Value{}, nil, false
+				}
+				allocated := reflect.New(v.Type().Elem())
+				if !v.IsNil() {
+					allocated.Elem().Set(v.Elem())
+				}
+				pointers = append(pointers, v)
+				allocations = append(allocations, allocated)
+				v = allocated
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	if len(pointers) == 0 {
+		return v, nil, v.CanSet()
+	}
+	commit := func() {
+		for i, pointer := range pointers {
+			pointer.Set(allocations[i])
+		}
+	}
+	return v, commit, v.CanSet()
+}
@@ -0,0 +1,5 @@
+module github.com/roserocket/gopartial
+
+go 1.22
+
+require github.com/guregu/null v4.0.0+incompatible
@@ -2,8 +2,10 @@ package gopartial
 
 import (
 	"errors"
-	"log"
+	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 )
 
 var errDestinationMustBeStructType = errors.New("Destination must be a struct type")
@@ -11,79 +13,570 @@ var errDestinationMustBePointerType = errors.New("Destination must be pointer to
 
 // PartialUpdate updates destination object (Must be a pointer to a struct)
 // from a map[string]interface{} where struct tag name is equals to the map key.
+// Tags are parsed like encoding/json: options after the comma are ignored except for ",string",
+// "-" skips the field and fields without a tag name are matched with their Go field name.
+// Nested struct fields are updated partially when their value is a map[string]interface{}.
 // This function can extended through updaters. A list of function that accepts
 // destination Value and the to be assigned Value and return true if updates is successful
-// Returns list of struct field names that was successfully updated.
-func PartialUpdate(dest interface{}, partial map[string]interface{}, tagName string, skipConditions []func(reflect.StructField) bool, updaters []func(reflect.Value, reflect.Value) bool) ([]string, error) {
+// Options can change how partial keys are matched to the struct fields.
+// Returns list of struct field names that was successfully updated, nested fields
+// are returned with their path (e.g. "Address.City"). Values that could not be assigned
+// don't stop the update, they are returned as a *PatchError along with the updated fields.
+func PartialUpdate(dest interface{}, partial map[string]interface{}, tagName string, skipConditions []func(reflect.StructField) bool, updaters []func(reflect.Value, reflect.Value) bool, opts ...Option) ([]string, error) {
 	valueOfDest := reflect.ValueOf(dest)
 	// Must be a pointer to a struct so that it can be updated
 	if valueOfDest.Kind() != reflect.Ptr {
 		return nil, errDestinationMustBePointerType
 	}
-	valueOfDest = valueOfDest.Elem()
 
-	typeOfDest := valueOfDest.Type()
+	return updated(apply(valueOfDest.Elem(), partial, newOptions(tagName, skipConditions, updaters, nil, opts), modeUpdate))
+}
+
+// Apply updates dest from partial like PartialUpdate does, with the struct tag name "json",
+// SkipConditions and DefaultRegistry unless opts say otherwise (see WithTag, WithSkipConditions,
+// WithRegistry and WithUpdaters). dest must be a pointer to a struct.
+func Apply[T any](dest *T, partial map[string]interface{}, opts ...Option) ([]string, error) {
+	if dest == nil {
+		return nil, errDestinationMustBePointerType
+	}
+	return updated(apply(reflect.ValueOf(dest).Elem(), partial, newOptions(defaultTagName, SkipConditions, nil, DefaultRegistry, opts), modeUpdate))
+}
+
+// Result describes what applying a partial did
+type Result struct {
+	// Updated is the list of struct field names that were successfully updated,
+	// nested fields are listed with their path (e.g. "Address.City")
+	Updated []string
+	// UnknownKeys is the list of partial keys that match no struct field, nested
+	// keys are listed with their path (e.g. "address.citty")
+	UnknownKeys []string
+	// Changes describes each of the fields the partial assigned a value to, including the
+	// unchanged ones left out of Updated by WithChangedOnly. It is filled by Patcher.Patch
+	// and Patcher.Preview.
+	Changes []Change
+}
+
+// Change describes a field updated by a partial
+type Change struct {
+	// Name is the Go name of the struct field, or the index of the element, e.g. "City"
+	Name string
+	// Key is the path of the partial key, e.g. "address.city"
+	Key string
+	// Path is the path of the struct field, e.g. "Address.City"
+	Path string
+	// Old is the value of the field before the update
+	Old interface{}
+	// New is the value of the field after the update
+	New interface{}
+	// Changed is whether New differs from Old (see WithChangedOnly)
+	Changed bool
+}
+
+// mode is what apply does with dest
+type mode int
+
+const (
+	// modeUpdate updates dest
+	modeUpdate mode = iota
+	// modeDescribe updates dest and describes the changes in the result
+	modeDescribe
+	// modePreview only describes the changes in the result, leaving dest untouched
+	modePreview
+)
+
+// apply updates the struct value valueOfDest from partial with o as told by m
+func apply(valueOfDest reflect.Value, partial map[string]interface{}, o *options, m mode) (*Result, error) {
 	// Must be a pointer to a struct so that it can be updated
-	if typeOfDest.Kind() != reflect.Struct {
+	if valueOfDest.Kind() != reflect.Struct {
 		return nil, errDestinationMustBeStructType
 	}
 
+	// in atomic and preview modes the partial is applied to a copy of dest, which is
+	// only written back to dest in atomic mode when every value could be assigned
+	dryRun := m == modePreview
+	target := valueOfDest
+	if o.atomic || dryRun {
+		target = reflect.New(valueOfDest.Type()).Elem()
+		target.Set(valueOfDest)
+	}
+
+	p := &patch{
+		options:       o,
+		fieldsUpdated: make([]string, 0, len(partial)),
+		copyOnWrite:   o.atomic || dryRun,
+		trackChanges:  m != modeUpdate,
+	}
+	if err := p.partialUpdate(target, partial, location{}); err != nil && err != errStopped {
+		return nil, err
+	}
+
+	result := &Result{
+		Updated:     p.fieldsUpdated,
+		UnknownKeys: p.unknownKeys,
+		Changes:     p.changes,
+	}
+	if len(p.fieldErrors) > 0 {
+		if o.atomic {
+			result.Updated = make([]string, 0)
+			result.Changes = nil
+		}
+		return result, &PatchError{Fields: p.fieldErrors}
+	}
+	if o.atomic && !dryRun {
+		valueOfDest.Set(target)
+	}
+	return result, nil
+}
+
+// updated returns the fields updated of result along with err
+func updated(result *Result, err error) ([]string, error) {
+	if result == nil {
+		return nil, err
+	}
+	return result.Updated, err
+}
+
+// patch is the state of a partial being applied to a struct
+type patch struct {
+	*options
+
 	// fieldsUpdated is to keep track all the field names that were successfuly updated
-	fieldsUpdated := make([]string, 0)
+	fieldsUpdated []string
+	// fieldErrors is to keep track of all the values that could not be assigned
+	fieldErrors []FieldError
+	// unknownKeys is to keep track of all the keys that match no field
+	unknownKeys []string
+	// copyOnWrite copies the pointed structs, slices and maps before updating them,
+	// so that the struct being updated shares nothing that is modified with dest
+	copyOnWrite bool
+	// trackChanges keeps track of the old and new values of the updated fields in changes
+	trackChanges bool
+	changes      []Change
+}
+
+// snapshot returns the value of fieldValue before it is updated when changes are tracked
+// or compared. Maps are merged in place unless they are copied on write, so they are copied.
+func (p *patch) snapshot(fieldValue reflect.Value) interface{} {
+	if !p.trackChanges && !p.changedOnly {
+		return nil
+	}
+	if fieldValue.Kind() == reflect.Map && !p.copyOnWrite {
+		return copyMap(fieldValue).Interface()
+	}
+	return fieldValue.Interface()
+}
 
-	for i := 0; i < typeOfDest.NumField(); i++ {
-		field := typeOfDest.Field(i)
+// updated keeps track of the field fieldValue at at that was updated from the value old
+func (p *patch) updated(fieldValue reflect.Value, at location, old interface{}) {
+	if !p.trackChanges && !p.changedOnly {
+		p.fieldsUpdated = append(p.fieldsUpdated, at.path)
+		return
+	}
+
+	value := fieldValue.Interface()
+	changed := !equal(reflect.ValueOf(old), reflect.ValueOf(value))
+	if changed || !p.changedOnly {
+		p.fieldsUpdated = append(p.fieldsUpdated, at.path)
+	}
+	if p.trackChanges {
+		p.changes = append(p.changes, Change{
+			Name:    at.name,
+			Key:     at.key,
+			Path:    at.path,
+			Old:     old,
+			New:     value,
+			Changed: changed,
+		})
+	}
+}
+
+// equal returns whether the values a and b are equal, using their Equal method when
+// they have one (e.g. time.Time, which DeepEqual compares with its monotonic clock)
+func equal(a reflect.Value, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	if a.Kind() == reflect.Ptr {
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return a.Pointer() == b.Pointer() || equal(a.Elem(), b.Elem())
+	}
+	if method, ok := a.Type().MethodByName("Equal"); ok {
+		methodType := method.Type
+		if methodType.NumIn() == 2 && methodType.In(1) == a.Type() && methodType.NumOut() == 1 && methodType.Out(0).Kind() == reflect.Bool {
+			return method.Func.Call([]reflect.Value{a, b})[0].Bool()
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// copyMap returns a shallow copy of the map m
+func copyMap(m reflect.Value) reflect.Value {
+	if m.IsNil() {
+		return m
+	}
+	copied := reflect.MakeMapWithSize(m.Type(), m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		copied.SetMapIndex(iter.Key(), iter.Value())
+	}
+	return copied
+}
+
+// errStopped stops the update on the first rejected value in fail-fast mode
+var errStopped = errors.New("Partial update stopped")
+
+// reject keeps track of a value that could not be assigned. It returns errStopped
+// in fail-fast mode so that the update stops there.
+func (p *patch) reject(fieldError FieldError) error {
+	p.fieldErrors = append(p.fieldErrors, fieldError)
+	if p.failFast {
+		return errStopped
+	}
+	return nil
+}
+
+// location is where a value is in the struct being updated and in the partial
+type location struct {
+	// name is the Go name of the struct field, or the index of the element
+	name string
+	// path is the path of the struct field, e.g. "Address.City"
+	path string
+	// key is the path of the partial key, e.g. "address.city"
+	key string
+}
+
+// child returns the location of the field name with the partial key key within l
+func (l location) child(name string, key string) location {
+	if l.path == "" {
+		return location{name: name, path: name, key: key}
+	}
+	return location{name: name, path: l.path + pathSeparator + name, key: l.key + pathSeparator + key}
+}
+
+// partialUpdate updates the struct value valueOfDest from partial, recursing into
+// nested struct fields whenever the partial value is a map[string]interface{}.
+// Updated fields are reported by their path from at, e.g. "Address.City".
+func (p *patch) partialUpdate(valueOfDest reflect.Value, partial map[string]interface{}, at location) error {
+	plan := planFor(valueOfDest.Type(), p.tagName)
+
+	// dot-path keys are applied like nested objects, unless a field is tagged with the dotted key itself
+	// conflicting keys reject the whole object, before any of its fields is updated
+	expanded, err := expandPaths(partial, func(key string) bool {
+		_, ok := plan.byName[key]
+		return ok
+	})
+	if conflict, ok := err.(*pathConflict); ok {
+		return p.reject(newConflictError(at, conflict, partial[conflict.path]))
+	}
+	partial = expanded
+
+	skip := plan.skipped(p.skipConditions)
+	keys := p.matchKeys(plan.fields, partial)
+	if err := p.checkUnknownKeys(plan, partial, keys, at); err != nil {
+		return err
+	}
+
+	for i, field := range plan.fields {
+		// get the partial value based on the tagName
+		key := field.name
+		ok := false
+		if keys != nil {
+			key, ok = keys[i]
+		} else {
+			_, ok = partial[key]
+		}
+		if !ok {
+			continue
+		}
+		val := partial[key]
+		if field.quoted {
+			val = unquote(field.Type, val)
+		}
+
+		// skip this field if any extended skip condition applies to it, or to
+		// one of the embedded structs it is promoted from
+		if skip[i] {
+			continue
+		}
 
 		// skip this field if it cant be set
-		if !valueOfDest.Field(i).CanSet() {
+		fieldValue, commit, ok := fieldByIndex(valueOfDest, field.index, p.copyOnWrite)
+		if !ok {
 			continue
 		}
 
-		skip := false
-		// go through all extended skip conditions
-		for _, skipCondition := range skipConditions {
-			skip = skipCondition(field)
-			if skip {
-				// break on the first skip condition found
-				break
-			}
+		fieldErrors, fieldsUpdated := len(p.fieldErrors), len(p.fieldsUpdated)
+		// the converter the field is tagged with takes precedence over the updaters of its type
+		if field.converter != "" {
+			err = p.updateWithConverter(fieldValue, val, at.child(field.Name, key), field.converter)
+		} else {
+			err = p.updateValue(fieldValue, val, at.child(field.Name, key))
+		}
+		// the embedded struct pointers allocated on the way are only set when the value was accepted
+		if commit != nil && (len(p.fieldErrors) == fieldErrors || len(p.fieldsUpdated) > fieldsUpdated) {
+			commit()
+		}
+		if err != nil {
+			return err
 		}
-		if skip {
+	}
+
+	return nil
+}
+
+// checkUnknownKeys keeps track of the keys of partial that didn't match any of the plan
+// fields, and rejects them in strict mode. keys are the matched keys by field position,
+// or nil when fields are matched by their exact names.
+func (p *patch) checkUnknownKeys(plan *structPlan, partial map[string]interface{}, keys map[int]string, at location) error {
+	var unknown []string
+	for key := range partial {
+		if keys == nil {
+			if _, ok := plan.byName[key]; ok {
+				continue
+			}
+		} else if isMatched(keys, key) {
 			continue
 		}
+		unknown = append(unknown, key)
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	// report the keys in order so that the errors are predictable
+	sort.Strings(unknown)
+
+	for _, key := range unknown {
+		unknownAt := at.child(key, key)
+		p.unknownKeys = append(p.unknownKeys, unknownAt.key)
+		if p.strict {
+			if err := p.reject(FieldError{
+				Path:     unknownAt.path,
+				Key:      unknownAt.key,
+				Received: received(reflect.ValueOf(partial[key])),
+				Reason:   reasonUnknownKey,
+			}); err != nil {
+				return err
+			}
+		}
+	}
 
-		// get the partial value based on the tagName
-		if val, ok := partial[field.Tag.Get(tagName)]; ok {
-			v := reflect.ValueOf(val)
-			updateSuccess := false
-
-			// easily assign the value if both end's kinds are the same
-			if valueOfDest.Field(i).Kind() == v.Kind() {
-				valueOfDest.Field(i).Set(v)
-				updateSuccess = true
-			} else {
-				// go through all extended process types
-				for _, updater := range updaters {
-					updateSuccess = updater(valueOfDest.Field(i), v)
-					if updateSuccess {
-						// the first updateSuccess found, break the loop
-						break
-					}
-				}
+	return nil
+}
+
+// isMatched reports whether key is one of the matched keys
+func isMatched(keys map[int]string, key string) bool {
+	for _, matched := range keys {
+		if matched == key {
+			return true
+		}
+	}
+	return false
+}
+
+// skipField reports whether any of the skip conditions applies to field or to
+// the embedded structs it is promoted from
+func skipField(field field, skipConditions []func(reflect.StructField) bool) bool {
+	// go through all extended skip conditions
+	for _, skipCondition := range skipConditions {
+		if skipCondition(field.StructField) {
+			// break on the first skip condition found
+			return true
+		}
+		for _, embedded := range field.embedded {
+			if skipCondition(embedded) {
+				return true
 			}
+		}
+	}
+
+	return false
+}
 
-			if updateSuccess {
-				fieldsUpdated = append(fieldsUpdated, field.Name)
-			} else {
-				if !v.IsValid() {
-					log.Printf("%v.%v cannot be assigned with value null", typeOfDest.Name(), field.Name)
-				} else {
-					log.Printf("%v.%v cannot be assigned with value %v", typeOfDest.Name(), field.Name, v.Interface())
-				}
+// updateValue assigns val to the field fieldValue at at, and keeps track of the paths that
+// were updated and of the values that could not be assigned. A map[string]interface{} val is
+// applied partially to struct fields (allocating nil pointers to struct) and to the elements of
+// slice and array fields.
+func (p *patch) updateValue(fieldValue reflect.Value, val interface{}, at location) error {
+	// a nested object only updates the keys present in it, leaving the rest of the value intact
+	if nested, isMap := val.(map[string]interface{}); isMap {
+		if fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct {
+			// allocate a new struct to apply the nested object to when the pointer is nil
+			if fieldValue.IsNil() {
+				old := p.snapshot(fieldValue)
+				fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+				p.updated(fieldValue, at, old)
+			} else if p.copyOnWrite {
+				copied := reflect.New(fieldValue.Type().Elem())
+				copied.Elem().Set(fieldValue.Elem())
+				fieldValue.Set(copied)
 			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		switch fieldValue.Kind() {
+		case reflect.Struct:
+			return p.partialUpdate(fieldValue, nested, at)
+		case reflect.Slice, reflect.Array:
+			return p.updateElements(fieldValue, nested, at)
+		}
+	}
+
+	v := reflect.ValueOf(val)
+	old := p.snapshot(fieldValue)
+	return p.converted(fieldValue, v, at, old, p.assign(fieldValue, v))
+}
+
+// assign assigns v to fieldValue, converting it to the field type when needed. It returns
+// errNotHandled when v can't be converted, or the error of the conversion that rejected it.
+func (p *patch) assign(fieldValue reflect.Value, v reflect.Value) error {
+	// easily assign the value if its type is assignable to the field type, except for maps
+	// which are merged by the updaters when possible and NaN or infinite floats they reject
+	if v.IsValid() && v.Kind() != reflect.Map && v.Type().AssignableTo(fieldValue.Type()) && isFinite(v) {
+		fieldValue.Set(v)
+		return nil
+	}
+
+	// updaters merge into the existing map, which must not be shared with dest
+	if p.copyOnWrite && fieldValue.Kind() == reflect.Map {
+		fieldValue.Set(copyMap(fieldValue))
+	}
+	err := p.convert(fieldValue, v)
+	// integer fields reject the floats with a fractional part unless they are truncated
+	if err != nil && p.truncateFloats {
+		if truncatedValue, ok := truncated(v); ok {
+			err = p.convert(fieldValue, truncatedValue)
 		}
+	}
+	// pointers are set to nil by null, or to a new value v is assigned to
+	if err == errNotHandled && fieldValue.Kind() == reflect.Ptr {
+		return assignPointer(fieldValue, v, p.assign)
+	}
+	// database/sql null types are set to invalid by null, or to a valid value v is assigned to
+	if err == errNotHandled && isSQLNull(fieldValue.Type()) {
+		return assignSQLNull(fieldValue, v, p.assign)
+	}
+	// maps with string keys are merged, their elements assigned like fields
+	if err == errNotHandled && p.registry != nil && mergeMap(fieldValue, v, p.assigned) {
+		return nil
+	}
+	// fall back to the unmarshaling interfaces of the field type
+	if err == errNotHandled {
+		err = unmarshal(fieldValue, v)
+	}
+	// convert the value to a named type of the same kind, e.g. a string to a `type Status string`
+	if err == errNotHandled && v.IsValid() && v.Kind() == fieldValue.Kind() && v.Type().ConvertibleTo(fieldValue.Type()) && isFinite(v) {
+		fieldValue.Set(v.Convert(fieldValue.Type()))
+		return nil
+	}
+	// replace the whole map when no updater could merge it
+	if err == errNotHandled && v.Kind() == reflect.Map && v.Type().AssignableTo(fieldValue.Type()) {
+		fieldValue.Set(v)
+		return nil
+	}
+	return err
+}
+
+// assigned assigns v to fieldValue like assign, and returns whether it succeeded
+func (p *patch) assigned(fieldValue reflect.Value, v reflect.Value) bool {
+	return p.assign(fieldValue, v) == nil
+}
+
+// assignPointer sets the pointer field fieldValue to nil for null, or else to a new value that
+// v is assigned to with assign, starting from the value fieldValue points to
+func assignPointer(fieldValue reflect.Value, v reflect.Value, assign func(reflect.Value, reflect.Value) error) error {
+	if !v.IsValid() {
+		fieldValue.Set(reflect.Zero(fieldValue.Type()))
+		return nil
+	}
+
+	elem := reflect.New(fieldValue.Type().Elem())
+	if !fieldValue.IsNil() {
+		elem.Elem().Set(fieldValue.Elem())
+		// maps are merged into, so the one pointed to must not be shared with the new value
+		if elem.Elem().Kind() == reflect.Map {
+			elem.Elem().Set(copyMap(elem.Elem()))
+		}
+	}
+	if err := assign(elem.Elem(), v); err != nil {
+		return err
+	}
+	fieldValue.Set(elem)
+	return nil
+}
+
+// updateWithConverter updates fieldValue at at with val through the converter called name
+func (p *patch) updateWithConverter(fieldValue reflect.Value, val interface{}, at location, name string) error {
+	v := reflect.ValueOf(val)
+	c, ok := p.converters[name]
+	if !ok {
+		return p.reject(newFieldError(at, fieldValue.Type(), v, fmt.Sprintf("%v %q", reasonUnknownConverter, name)))
+	}
+
+	old := p.snapshot(fieldValue)
+	// the converter may update the existing map, which must not be shared with dest
+	if p.copyOnWrite && fieldValue.Kind() == reflect.Map {
+		fieldValue.Set(copyMap(fieldValue))
+	}
+	return p.converted(fieldValue, v, at, old, c(fieldValue, v))
+}
+
+// converted keeps track of fieldValue at at updated from old with v, or rejects v
+// when err is not nil
+func (p *patch) converted(fieldValue reflect.Value, v reflect.Value, at location, old interface{}, err error) error {
+	if err == nil {
+		p.updated(fieldValue, at, old)
+		return nil
+	}
+
+	fieldError := newFieldError(at, fieldValue.Type(), v, reasonIncompatible)
+	if err != errNotHandled {
+		fieldError.Reason = err.Error()
+		fieldError.Err = err
+	}
+	return p.reject(fieldError)
+}
 
+// updateElements updates the elements of the slice or array fieldValue at at whose
+// indexes are the keys of partial, e.g. {"0": {"qty": 2}}.
+func (p *patch) updateElements(fieldValue reflect.Value, partial map[string]interface{}, at location) error {
+	keys := make([]string, 0, len(partial))
+	for key := range partial {
+		keys = append(keys, key)
+	}
+	// update the elements in order so that the updated paths are predictable
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA != nil || errB != nil {
+			return errA == nil || (errB != nil && keys[i] < keys[j])
+		}
+		return a < b
+	})
+
+	// the elements are updated in place, so the slice must not be shared with dest
+	if p.copyOnWrite && fieldValue.Kind() == reflect.Slice {
+		copied := reflect.MakeSlice(fieldValue.Type(), fieldValue.Len(), fieldValue.Len())
+		reflect.Copy(copied, fieldValue)
+		fieldValue.Set(copied)
+	}
+
+	for _, key := range keys {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= fieldValue.Len() {
+			if err := p.reject(newFieldError(at.child(key, key), fieldValue.Type().Elem(), reflect.ValueOf(partial[key]), reasonNoElement)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.updateValue(fieldValue.Index(index), partial[key], at.child(strconv.Itoa(index), key)); err != nil {
+			return err
+		}
 	}
 
-	return fieldsUpdated, nil
+	return nil
 }
@@ -1,6 +1,10 @@
 package gopartial
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -128,7 +132,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// null.String
@@ -172,7 +176,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// float64
@@ -286,7 +290,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// *float64
@@ -414,7 +418,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// null.Float
@@ -542,7 +546,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// int
@@ -627,8 +631,8 @@ func TestPartialUpdate(t *testing.T) {
 				updaters:       Updaters,
 				skipConditions: SkipConditions,
 			},
-			want:    []string{"Field5"},
-			wantErr: false,
+			want:    []string{},
+			wantErr: true,
 		},
 		test{
 			name: "Update field5 (int) with float64",
@@ -641,8 +645,8 @@ func TestPartialUpdate(t *testing.T) {
 				updaters:       Updaters,
 				skipConditions: SkipConditions,
 			},
-			want:    []string{"Field5"},
-			wantErr: false,
+			want:    []string{},
+			wantErr: true,
 		},
 		test{
 			name: "Update field5 (int) to string",
@@ -656,7 +660,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// *int
@@ -755,8 +759,8 @@ func TestPartialUpdate(t *testing.T) {
 				updaters:       Updaters,
 				skipConditions: SkipConditions,
 			},
-			want:    []string{"Field5p"},
-			wantErr: false,
+			want:    []string{},
+			wantErr: true,
 		},
 		test{
 			name: "Update field5p (*int) with float64",
@@ -769,8 +773,8 @@ func TestPartialUpdate(t *testing.T) {
 				updaters:       Updaters,
 				skipConditions: SkipConditions,
 			},
-			want:    []string{"Field5p"},
-			wantErr: false,
+			want:    []string{},
+			wantErr: true,
 		},
 		test{
 			name: "Update field5p (*int) to string",
@@ -784,7 +788,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// null.Int
@@ -869,8 +873,8 @@ func TestPartialUpdate(t *testing.T) {
 				updaters:       Updaters,
 				skipConditions: SkipConditions,
 			},
-			want:    []string{"Field6"},
-			wantErr: false,
+			want:    []string{},
+			wantErr: true,
 		},
 		test{
 			name: "Update field6 (null.Int) with float64",
@@ -883,8 +887,8 @@ func TestPartialUpdate(t *testing.T) {
 				updaters:       Updaters,
 				skipConditions: SkipConditions,
 			},
-			want:    []string{"Field6"},
-			wantErr: false,
+			want:    []string{},
+			wantErr: true,
 		},
 		test{
 			name: "Update field6 (null.Int) with null",
@@ -912,7 +916,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// bool
@@ -942,7 +946,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 		test{
 			name: "Update field7 (bool) with int",
@@ -956,7 +960,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// *bool
@@ -1000,7 +1004,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// time.Time
@@ -1030,7 +1034,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 		test{
 			name: "Update field9 (time.Time) with int",
@@ -1044,7 +1048,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// *time.Time
@@ -1088,7 +1092,7 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
-			wantErr: false,
+			wantErr: true,
 		},
 
 		// null.Time
@@ -1132,8 +1136,150 @@ func TestPartialUpdate(t *testing.T) {
 				skipConditions: SkipConditions,
 			},
 			want:    []string{},
+			wantErr: true,
+		},
+
+		// nested struct
+		test{
+			name: "Update field11 (sub) with map",
+			args: args{
+				dest: &destination{},
+				partial: map[string]interface{}{
+					"field11": map[string]interface{}{
+						"fielda": str,
+					},
+				},
+				tagName:        "json",
+				updaters:       Updaters,
+				skipConditions: SkipConditions,
+			},
+			want:    []string{"Field11.FieldA"},
 			wantErr: false,
 		},
+		test{
+			name: "Update field11 (sub) with empty map",
+			args: args{
+				dest: &destination{},
+				partial: map[string]interface{}{
+					"field11": map[string]interface{}{},
+				},
+				tagName:        "json",
+				updaters:       Updaters,
+				skipConditions: SkipConditions,
+			},
+			want:    []string{},
+			wantErr: false,
+		},
+		test{
+			name: "Update field11 (sub) with string",
+			args: args{
+				dest: &destination{},
+				partial: map[string]interface{}{
+					"field11": str,
+				},
+				tagName:        "json",
+				updaters:       Updaters,
+				skipConditions: SkipConditions,
+			},
+			want:    []string{},
+			wantErr: true,
+		},
+
+		// *sub
+		test{
+			name: "Update field11p (*sub) with map",
+			args: args{
+				dest: &destination{},
+				partial: map[string]interface{}{
+					"field11p": map[string]interface{}{
+						"fielda": str,
+					},
+				},
+				tagName:        "json",
+				updaters:       Updaters,
+				skipConditions: SkipConditions,
+			},
+			want:    []string{"Field11p", "Field11p.FieldA"},
+			wantErr: false,
+		},
+		test{
+			name: "Update field11p (*sub, not nil) with map",
+			args: args{
+				dest: &destination{Field11p: &sub{}},
+				partial: map[string]interface{}{
+					"field11p": map[string]interface{}{
+						"fielda": str,
+					},
+				},
+				tagName:        "json",
+				updaters:       Updaters,
+				skipConditions: SkipConditions,
+			},
+			want:    []string{"Field11p.FieldA"},
+			wantErr: false,
+		},
+		test{
+			name: "Update field11p (*sub) with null",
+			args: args{
+				dest: &destination{Field11p: &sub{}},
+				partial: map[string]interface{}{
+					"field11p": nil,
+				},
+				tagName:        "json",
+				updaters:       Updaters,
+				skipConditions: SkipConditions,
+			},
+			want:    []string{"Field11p"},
+			wantErr: false,
+		},
+		test{
+			name: "Update field11p (*sub) with string",
+			args: args{
+				dest: &destination{},
+				partial: map[string]interface{}{
+					"field11p": str,
+				},
+				tagName:        "json",
+				updaters:       Updaters,
+				skipConditions: SkipConditions,
+			},
+			want:    []string{},
+			wantErr: true,
+		},
+
+		// dot-path keys
+		test{
+			name: "Update field11 (sub) with dot-path key",
+			args: args{
+				dest: &destination{},
+				partial: map[string]interface{}{
+					"field11.fielda": str,
+					"field11.fieldb": str,
+				},
+				tagName:        "json",
+				updaters:       Updaters,
+				skipConditions: SkipConditions,
+			},
+			want:    []string{"Field11.FieldA", "Field11.FieldB"},
+			wantErr: false,
+		},
+		test{
+			name: "Update field11 (sub) with dot-path key and map",
+			args: args{
+				dest: &destination{},
+				partial: map[string]interface{}{
+					"field11.fielda": str,
+					"field11": map[string]interface{}{
+						"fieldb": str,
+					},
+				},
+				tagName:        "json",
+				updaters:       Updaters,
+				skipConditions: SkipConditions,
+			},
+			want:    []string{},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1143,9 +1289,859 @@ func TestPartialUpdate(t *testing.T) {
 				t.Errorf("PartialUpdate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			// the fields updated are returned along with the errors of the other fields
+			var patchErr *PatchError
+			if err != nil && !errors.As(err, &patchErr) {
+				return
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("PartialUpdate() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestPartialUpdateNested(t *testing.T) {
+	type address struct {
+		Street string `json:"street"`
+		City   string `json:"city"`
+	}
+	type user struct {
+		Name    string  `json:"name"`
+		Address address `json:"address"`
+	}
+
+	dest := &user{Name: "John", Address: address{Street: "Main St", City: "Toronto"}}
+	got, err := PartialUpdate(dest, map[string]interface{}{
+		"address": map[string]interface{}{
+			"city": "Paris",
+		},
+	}, "json", SkipConditions, Updaters)
+	if err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if want := []string{"Address.City"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PartialUpdate() = %v, want %v", got, want)
+	}
+	if want := (user{Name: "John", Address: address{Street: "Main St", City: "Paris"}}); !reflect.DeepEqual(*dest, want) {
+		t.Errorf("PartialUpdate() dest = %+v, want %+v", *dest, want)
+	}
+}
+
+func TestPartialUpdateNestedPointer(t *testing.T) {
+	type contact struct {
+		Name  string `json:"name"`
+		Phone string `json:"phone"`
+	}
+	type order struct {
+		Shipping *contact `json:"shipping"`
+	}
+
+	dest := &order{}
+	if _, err := PartialUpdate(dest, map[string]interface{}{
+		"shipping": map[string]interface{}{"name": "John"},
+	}, "json", SkipConditions, Updaters); err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if dest.Shipping == nil || *dest.Shipping != (contact{Name: "John"}) {
+		t.Fatalf("PartialUpdate() shipping = %+v, want %+v", dest.Shipping, contact{Name: "John"})
+	}
+
+	shipping := dest.Shipping
+	if _, err := PartialUpdate(dest, map[string]interface{}{
+		"shipping": map[string]interface{}{"phone": "555"},
+	}, "json", SkipConditions, Updaters); err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if dest.Shipping != shipping || *dest.Shipping != (contact{Name: "John", Phone: "555"}) {
+		t.Fatalf("PartialUpdate() shipping = %+v, want %+v", dest.Shipping, contact{Name: "John", Phone: "555"})
+	}
+
+	if _, err := PartialUpdate(dest, map[string]interface{}{
+		"shipping": nil,
+	}, "json", SkipConditions, Updaters); err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if dest.Shipping != nil {
+		t.Errorf("PartialUpdate() shipping = %+v, want nil", dest.Shipping)
+	}
+}
+
+func TestPartialUpdatePaths(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip"`
+	}
+	type order struct {
+		Address address `json:"address"`
+		Items   []item  `json:"items"`
+		Note    string  `json:"note.text"`
+	}
+
+	dest := &order{
+		Address: address{City: "Toronto", Zip: "M5V"},
+		Items:   []item{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 1}},
+	}
+	got, err := PartialUpdate(dest, map[string]interface{}{
+		"address.city": "Paris",
+		"items.1.qty":  3,
+		"note.text":    "fragile",
+	}, "json", SkipConditions, Updaters)
+	if err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if want := []string{"Address.City", "Items.1.Qty", "Note"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PartialUpdate() = %v, want %v", got, want)
+	}
+	want := order{
+		Address: address{City: "Paris", Zip: "M5V"},
+		Items:   []item{{SKU: "a", Qty: 1}, {SKU: "b", Qty: 3}},
+		Note:    "fragile",
+	}
+	if !reflect.DeepEqual(*dest, want) {
+		t.Errorf("PartialUpdate() dest = %+v, want %+v", *dest, want)
+	}
+
+	conflicts := []map[string]interface{}{
+		{"address.city": "Paris", "address": map[string]interface{}{"zip": "75001"}},
+		{"address.city": "Paris", "address.city.name": "Paris"},
+		{"items.0": map[string]interface{}{}, "items.0.qty": 2},
+	}
+	for i, partial := range conflicts {
+		_, err := PartialUpdate(dest, partial, "json", SkipConditions, Updaters)
+		var conflictErr *PatchError
+		if !errors.As(err, &conflictErr) || len(conflictErr.Fields) != 1 {
+			t.Errorf("PartialUpdate(%v) error = %v, want conflict", partial, err)
+			continue
+		}
+		if want := []string{"string", "string", "int"}[i]; conflictErr.Fields[0].Received != want {
+			t.Errorf("PartialUpdate(%v) received = %v, want %v", partial, conflictErr.Fields[0].Received, want)
+		}
+	}
+
+	// a conflict within a nested object rejects that object, the other fields are still updated
+	type nested struct {
+		A  string `json:"a"`
+		In order  `json:"in"`
+	}
+	nestedDest := &nested{}
+	got, err = PartialUpdate(nestedDest, map[string]interface{}{
+		"a":  "set",
+		"in": map[string]interface{}{"address.city": "Paris", "address": map[string]interface{}{}},
+	}, "json", SkipConditions, Updaters)
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) || len(patchErr.Fields) != 1 || patchErr.Fields[0].Key != "in.address.city" {
+		t.Fatalf("PartialUpdate() error = %v, want a conflict for in.address.city", err)
+	}
+	if patchErr.Fields[0].Received != "string" {
+		t.Errorf("PartialUpdate() received = %v, want string", patchErr.Fields[0].Received)
+	}
+	if !reflect.DeepEqual(got, []string{"A"}) || nestedDest.A != "set" || nestedDest.In.Address.City != "" {
+		t.Errorf("PartialUpdate() = %v, dest = %+v, want only A updated", got, *nestedDest)
+	}
+}
+
+func TestMapStringInterfaceUpdater(t *testing.T) {
+	type settings struct {
+		Meta   map[string]interface{} `json:"meta"`
+		Limits map[string]int         `json:"limits"`
+	}
+
+	dest := &settings{
+		Meta: map[string]interface{}{
+			"color": "red",
+			"size":  "xl",
+			"dims":  map[string]interface{}{"w": 1.0, "h": 2.0},
+		},
+	}
+	got, err := PartialUpdate(dest, map[string]interface{}{
+		"meta": map[string]interface{}{
+			"size":  nil,
+			"shape": "round",
+			"dims":  map[string]interface{}{"h": nil, "d": 3.0},
+		},
+		"limits": map[string]interface{}{
+			"daily": 10.0,
+		},
+	}, "json", SkipConditions, Updaters)
+	if err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if want := []string{"Meta", "Limits"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PartialUpdate() = %v, want %v", got, want)
+	}
+	want := settings{
+		Meta: map[string]interface{}{
+			"color": "red",
+			"shape": "round",
+			"dims":  map[string]interface{}{"w": 1.0, "d": 3.0},
+		},
+		Limits: map[string]int{"daily": 10},
+	}
+	if !reflect.DeepEqual(*dest, want) {
+		t.Errorf("PartialUpdate() dest = %+v, want %+v", *dest, want)
+	}
+
+	// the map is left untouched when an entry can't be converted
+	got, err = PartialUpdate(dest, map[string]interface{}{
+		"limits": map[string]interface{}{"daily": 20, "weekly": "many"},
+	}, "json", SkipConditions, Updaters)
+	if err == nil {
+		t.Fatalf("PartialUpdate() error = nil, want error")
+	}
+	if len(got) != 0 || !reflect.DeepEqual(dest.Limits, map[string]int{"daily": 10}) {
+		t.Errorf("PartialUpdate() = %v, limits = %v", got, dest.Limits)
+	}
+
+	got, err = PartialUpdate(dest, map[string]interface{}{"meta": nil}, "json", SkipConditions, Updaters)
+	if err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"Meta"}) || dest.Meta != nil {
+		t.Errorf("PartialUpdate() = %v, meta = %v", got, dest.Meta)
+	}
+
+	// the elements are converted with the updaters in use
+	type account struct {
+		Balances map[string]testCents `json:"balances"`
+		Savings  sql.Null[testCents]  `json:"savings"`
+	}
+	centsUpdater := func(fieldValue reflect.Value, v reflect.Value) bool {
+		if fieldValue.Type() != reflect.TypeOf(testCents(0)) || v.Kind() != reflect.String {
+			return false
+		}
+		var units, cents int64
+		if _, err := fmt.Sscanf(v.String(), "%d.%02d", &units, &cents); err != nil {
+			return false
+		}
+		fieldValue.SetInt(units*100 + cents)
+		return true
+	}
+	updaters := append([]func(reflect.Value, reflect.Value) bool{centsUpdater}, Updaters...)
+	accountPartial := map[string]interface{}{"balances": map[string]interface{}{"usd": "1.50"}, "savings": "2.25"}
+	wantAccount := account{Balances: map[string]testCents{"usd": 150}, Savings: sql.Null[testCents]{V: 225, Valid: true}}
+	accountDest := &account{}
+	if _, err := PartialUpdate(accountDest, accountPartial, "json", SkipConditions, updaters); err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if !reflect.DeepEqual(*accountDest, wantAccount) {
+		t.Errorf("PartialUpdate() dest = %+v, want %+v", *accountDest, wantAccount)
+	}
+	accountDest = &account{}
+	if _, err := NewPatcher(WithUpdaters(updaters...)).Apply(accountDest, accountPartial); err != nil {
+		t.Fatalf("Patcher.Apply() error = %v", err)
+	}
+	if !reflect.DeepEqual(*accountDest, wantAccount) {
+		t.Errorf("Patcher.Apply() dest = %+v, want %+v", *accountDest, wantAccount)
+	}
+}
+
+func TestPartialUpdateEmbedded(t *testing.T) {
+	type BaseModel struct {
+		ID        string    `json:"id"`
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	type Audit struct {
+		UpdatedBy string `json:"updated_by" props:"readonly"`
+	}
+	type Owner struct {
+		ID string `json:"id"`
+	}
+	type account struct {
+		BaseModel
+		*Owner
+		Audit `props:"readonly"`
+		Name  string `json:"name"`
+	}
+
+	dest := &account{BaseModel: BaseModel{ID: "1", Name: "base"}}
+	got, err := PartialUpdate(dest, map[string]interface{}{
+		"created_at": "2017-11-22T20:30:26.716Z",
+		"name":       "John",
+		"updated_by": "admin",
+	}, "json", SkipConditions, Updaters)
+	if err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if want := []string{"CreatedAt", "Name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PartialUpdate() = %v, want %v", got, want)
+	}
+	// the name of the embedded struct is shadowed by the outer one
+	if dest.Name != "John" || dest.BaseModel.Name != "base" || dest.CreatedAt.IsZero() || dest.UpdatedBy != "" {
+		t.Errorf("PartialUpdate() dest = %+v", *dest)
+	}
+	// the id is ambiguous between BaseModel and Owner, so the nil Owner is not allocated
+	if _, err := PartialUpdate(dest, map[string]interface{}{"id": "2"}, "json", SkipConditions, Updaters); err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if dest.BaseModel.ID != "1" || dest.Owner != nil {
+		t.Errorf("PartialUpdate() dest = %+v", *dest)
+	}
+
+	type Contact struct {
+		Email   string `json:"email"`
+		Version int    `json:"version"`
+	}
+	type customer struct {
+		*Contact
+	}
+	// the nil Contact is not allocated for a rejected value
+	c := &customer{}
+	got, err = PartialUpdate(c, map[string]interface{}{"version": "nope"}, "json", SkipConditions, Updaters)
+	if err == nil || len(got) != 0 || c.Contact != nil {
+		t.Errorf("PartialUpdate() = %v, %v, dest = %+v, want the value rejected", got, err, c.Contact)
+	}
+	got, err = PartialUpdate(c, map[string]interface{}{"email": "john@example.com"}, "json", SkipConditions, Updaters)
+	if err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if want := []string{"Email"}; !reflect.DeepEqual(got, want) || c.Contact == nil || c.Email != "john@example.com" {
+		t.Errorf("PartialUpdate() = %v, dest = %+v", got, c.Contact)
+	}
+}
+
+func TestPartialUpdateTags(t *testing.T) {
+	type profile struct {
+		Name     string   `json:"name,omitempty"`
+		Nickname string   `json:",omitempty"`
+		Secret   string   `json:"-"`
+		Dash     string   `json:"-,"`
+		Age      int      `json:"age,string"`
+		Score    *float64 `json:"score,string"`
+		Active   bool     `json:"active,omitempty,string"`
+		Title    string
+	}
+
+	dest := &profile{}
+	got, err := PartialUpdate(dest, map[string]interface{}{
+		"name":     "John",
+		"Nickname": "Johnny",
+		"Secret":   "x",
+		"-":        "dash",
+		"age":      "21",
+		"score":    "9.5",
+		"active":   "true",
+		"Title":    "Mr",
+	}, "json", SkipConditions, Updaters)
+	if err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if want := []string{"Name", "Nickname", "Dash", "Age", "Score", "Active", "Title"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PartialUpdate() = %v, want %v", got, want)
+	}
+	score := 9.5
+	want := profile{Name: "John", Nickname: "Johnny", Dash: "dash", Age: 21, Score: &score, Active: true, Title: "Mr"}
+	if !reflect.DeepEqual(*dest, want) {
+		t.Errorf("PartialUpdate() dest = %+v, want %+v", *dest, want)
+	}
+}
+
+func TestPartialUpdateKeyMatching(t *testing.T) {
+	type person struct {
+		FirstName string `json:"first_name"`
+		LastName  string
+		Email     string `json:"email"`
+		EMail     string `json:"EMAIL"`
+	}
+
+	tests := []struct {
+		name    string
+		partial map[string]interface{}
+		opts    []Option
+		want    []string
+	}{
+		{
+			name:    "exact match only by default",
+			partial: map[string]interface{}{"FirstName": "John", "lastname": "Doe"},
+			want:    []string{},
+		},
+		{
+			name:    "case insensitive",
+			partial: map[string]interface{}{"FIRST_NAME": "John", "lastname": "Doe"},
+			opts:    []Option{WithCaseInsensitiveKeys()},
+			want:    []string{"FirstName", "LastName"},
+		},
+		{
+			name:    "field name fallback",
+			partial: map[string]interface{}{"FirstName": "John", "firstname": "Jack"},
+			opts:    []Option{WithFieldNameFallback()},
+			want:    []string{"FirstName"},
+		},
+		{
+			name:    "case insensitive field name fallback",
+			partial: map[string]interface{}{"firstname": "John"},
+			opts:    []Option{WithCaseInsensitiveKeys(), WithFieldNameFallback()},
+			want:    []string{"FirstName"},
+		},
+		{
+			name:    "exact match preferred",
+			partial: map[string]interface{}{"email": "a@example.com", "EMAIL": "b@example.com", "Email": "c@example.com"},
+			opts:    []Option{WithCaseInsensitiveKeys(), WithFieldNameFallback()},
+			want:    []string{"Email", "EMail"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := &person{}
+			got, err := PartialUpdate(dest, tt.partial, "json", SkipConditions, Updaters, tt.opts...)
+			if err != nil {
+				t.Fatalf("PartialUpdate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PartialUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	dest := &person{}
+	if _, err := PartialUpdate(dest, map[string]interface{}{"email": "a@example.com", "EMAIL": "b@example.com"}, "json", SkipConditions, Updaters, WithCaseInsensitiveKeys()); err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if dest.Email != "a@example.com" || dest.EMail != "b@example.com" {
+		t.Errorf("PartialUpdate() dest = %+v", *dest)
+	}
+}
+
+func TestApply(t *testing.T) {
+	type user struct {
+		ID   string `json:"id" props:"readonly"`
+		Name string `json:"name" db:"user_name"`
+		Age  *int   `json:"age"`
+	}
+
+	dest := &user{ID: "1"}
+	got, err := Apply(dest, map[string]interface{}{"id": "2", "name": "John", "age": 21.0})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if want := []string{"Name", "Age"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+	if dest.ID != "1" || dest.Name != "John" || dest.Age == nil || *dest.Age != 21 {
+		t.Errorf("Apply() dest = %+v", *dest)
+	}
+
+	got, err = Apply(dest, map[string]interface{}{"ID": "2", "user_name": "Jack", "age": 22.0},
+		WithTag("db"), WithSkipConditions(), WithUpdaters())
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if want := []string{"ID", "Name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+
+	if _, err := Apply((*user)(nil), map[string]interface{}{}); err == nil {
+		t.Errorf("Apply() with nil dest error = nil, want error")
+	}
+	str := "foo"
+	if _, err := Apply(&str, map[string]interface{}{}); err == nil {
+		t.Errorf("Apply() with pointer to string error = nil, want error")
+	}
+}
+
+func TestPartialUpdateFieldErrors(t *testing.T) {
+	type item struct {
+		Qty int `json:"qty"`
+	}
+	type order struct {
+		Name  string   `json:"name"`
+		Note  string   `json:"note"`
+		Price null.Int `json:"price"`
+		Items []item   `json:"items"`
+	}
+
+	dest := &order{Items: []item{{Qty: 1}}}
+	got, err := PartialUpdate(dest, map[string]interface{}{
+		"name":  1.5,
+		"note":  "fragile",
+		"price": nil,
+		"items": map[string]interface{}{
+			"0": map[string]interface{}{"qty": "two"},
+			"3": map[string]interface{}{"qty": 1},
+		},
+	}, "json", SkipConditions, Updaters)
+
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) {
+		t.Fatalf("PartialUpdate() error = %v, want *PatchError", err)
+	}
+	if want := []string{"Note", "Price"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PartialUpdate() = %v, want %v", got, want)
+	}
+	want := []FieldError{
+		{Path: "Name", Key: "name", Expected: "string", Received: "float64", Reason: reasonIncompatible},
+		{Path: "Items.0.Qty", Key: "items.0.qty", Expected: "int", Received: "string", Reason: reasonIncompatible},
+		{Path: "Items.3", Key: "items.3", Expected: "gopartial.item", Received: "map", Reason: reasonNoElement},
+	}
+	if !reflect.DeepEqual(patchErr.Fields, want) {
+		t.Errorf("PartialUpdate() field errors = %+v, want %+v", patchErr.Fields, want)
+	}
+}
+
+type testStatus string
+
+type testCode string
+
+func TestPartialUpdateAssignability(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type item struct {
+		Name   *string     `json:"name"`
+		Tags   []string    `json:"tags"`
+		Status testStatus  `json:"status"`
+		Codes  []testCode  `json:"codes"`
+		Extra  interface{} `json:"extra"`
+		Sub    address     `json:"sub"`
+	}
+	one := 1
+	tests := []struct {
+		name    string
+		partial map[string]interface{}
+		want    []string
+		wantErr bool
+	}{
+		{name: "*int to *string", partial: map[string]interface{}{"name": &one}, want: []string{}, wantErr: true},
+		{name: "[]int to []string", partial: map[string]interface{}{"tags": []int{1}}, want: []string{}, wantErr: true},
+		{name: "[]string to []testCode", partial: map[string]interface{}{"codes": []string{"a"}}, want: []string{}, wantErr: true},
+		{name: "struct to another struct", partial: map[string]interface{}{"sub": struct{ Town string }{}}, want: []string{}, wantErr: true},
+		{name: "string to named string", partial: map[string]interface{}{"status": "active"}, want: []string{"Status"}},
+		{name: "named string to another named string", partial: map[string]interface{}{"status": testCode("active")}, want: []string{"Status"}},
+		{name: "[]testCode to []testCode", partial: map[string]interface{}{"codes": []testCode{"a"}}, want: []string{"Codes"}},
+		{name: "any value to interface{}", partial: map[string]interface{}{"extra": []int{1}}, want: []string{"Extra"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := &item{}
+			got, err := PartialUpdate(dest, tt.partial, "json", SkipConditions, Updaters)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PartialUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PartialUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	dest := &item{}
+	if _, err := Apply(dest, map[string]interface{}{"status": "active", "extra": 1.5}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if dest.Status != "active" || dest.Extra != 1.5 {
+		t.Errorf("Apply() dest = %+v", *dest)
+	}
+}
+
+func TestPartialUpdateNumericChecks(t *testing.T) {
+	type numbers struct {
+		Int     int        `json:"int"`
+		Int8    int8       `json:"int8"`
+		Int16p  *int16     `json:"int16p"`
+		Int64   int64      `json:"int64"`
+		Float32 float32    `json:"float32"`
+		Float64 float64    `json:"float64"`
+		NullInt null.Int   `json:"null_int"`
+		NullF   null.Float `json:"null_float"`
+		Uint    uint       `json:"uint"`
+		Uint8   uint8      `json:"uint8"`
+		Uint64p *uint64    `json:"uint64p"`
+		Uintptr uintptr    `json:"uintptr"`
+	}
+	maxUint64 := uint64(math.MaxUint64)
+	tests := []struct {
+		name    string
+		partial map[string]interface{}
+		opts    []Option
+		want    numbers
+		wantErr error
+	}{
+		{name: "integral float to int", partial: map[string]interface{}{"int": 2.0}, want: numbers{Int: 2}},
+		{name: "int fitting in int8", partial: map[string]interface{}{"int8": -128}, want: numbers{Int8: -128}},
+		{name: "float fitting in int8", partial: map[string]interface{}{"int8": 127.0}, want: numbers{Int8: 127}},
+		{name: "int overflowing int8", partial: map[string]interface{}{"int8": 300}, wantErr: ErrOutOfRange},
+		{name: "float overflowing int8", partial: map[string]interface{}{"int8": 128.0}, wantErr: ErrOutOfRange},
+		{name: "int overflowing *int16", partial: map[string]interface{}{"int16p": 40000}, wantErr: ErrOutOfRange},
+		{name: "float overflowing int64", partial: map[string]interface{}{"int64": 1e19}, wantErr: ErrOutOfRange},
+		{name: "non-integral float to int", partial: map[string]interface{}{"int": 1.9}, wantErr: ErrNotIntegral},
+		{name: "non-integral float to null.Int", partial: map[string]interface{}{"null_int": 1.5}, wantErr: ErrNotIntegral},
+		{name: "NaN to int", partial: map[string]interface{}{"int": math.NaN()}, wantErr: ErrNotFinite},
+		{name: "infinity to float64", partial: map[string]interface{}{"float64": math.Inf(1)}, wantErr: ErrNotFinite},
+		{name: "NaN to null.Float", partial: map[string]interface{}{"null_float": math.NaN()}, wantErr: ErrNotFinite},
+		{name: "float overflowing float32", partial: map[string]interface{}{"float32": 1e40}, wantErr: ErrOutOfRange},
+		{name: "uint to int", partial: map[string]interface{}{"int": uint8(3)}, want: numbers{Int: 3}},
+		{name: "uint overflowing int64", partial: map[string]interface{}{"int64": uint64(math.MaxUint64)}, wantErr: ErrOutOfRange},
+		{name: "uint to float", partial: map[string]interface{}{"float64": uint(3)}, want: numbers{Float64: 3}},
+		{name: "uint to null.Int", partial: map[string]interface{}{"null_int": uint32(3)}, want: numbers{NullInt: null.IntFrom(3)}},
+		{name: "uint to null.Float", partial: map[string]interface{}{"null_float": uint16(3)}, want: numbers{NullF: null.FloatFrom(3)}},
+		{name: "int to uint", partial: map[string]interface{}{"uint": 3}, want: numbers{Uint: 3}},
+		{name: "float to uint", partial: map[string]interface{}{"uint8": 255.0}, want: numbers{Uint8: 255}},
+		{name: "uint to uintptr", partial: map[string]interface{}{"uintptr": uint(3)}, want: numbers{Uintptr: 3}},
+		{name: "max uint64 to *uint64", partial: map[string]interface{}{"uint64p": maxUint64}, want: numbers{Uint64p: &maxUint64}},
+		{name: "negative int to uint", partial: map[string]interface{}{"uint": -1}, wantErr: ErrOutOfRange},
+		{name: "negative float to *uint64", partial: map[string]interface{}{"uint64p": -1.0}, wantErr: ErrOutOfRange},
+		{name: "int overflowing uint8", partial: map[string]interface{}{"uint8": 256}, wantErr: ErrOutOfRange},
+		{name: "uint overflowing uint8", partial: map[string]interface{}{"uint8": uint16(256)}, wantErr: ErrOutOfRange},
+		{name: "float overflowing *uint64", partial: map[string]interface{}{"uint64p": 2e19}, wantErr: ErrOutOfRange},
+		{name: "non-integral float to uint", partial: map[string]interface{}{"uint": 1.5}, wantErr: ErrNotIntegral},
+		{name: "float fitting in float32", partial: map[string]interface{}{"float32": 1.5}, want: numbers{Float32: 1.5}},
+		{
+			name:    "truncated floats",
+			partial: map[string]interface{}{"int": 1.9, "int8": -1.9, "null_int": 2.5},
+			opts:    []Option{WithFloatTruncation()},
+			want:    numbers{Int: 1, Int8: -1, NullInt: null.IntFrom(2)},
+		},
+		{
+			name:    "truncated float overflowing int8",
+			partial: map[string]interface{}{"int8": 128.5},
+			opts:    []Option{WithFloatTruncation()},
+			wantErr: ErrOutOfRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := &numbers{}
+			_, err := Apply(dest, tt.partial, tt.opts...)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*dest, tt.want) {
+				t.Errorf("Apply() dest = %+v, want %+v", *dest, tt.want)
+			}
+
+			dest = &numbers{}
+			_, err = PartialUpdate(dest, tt.partial, "json", SkipConditions, Updaters, tt.opts...)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("PartialUpdate() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(*dest, tt.want) {
+				t.Errorf("PartialUpdate() dest = %+v, want %+v", *dest, tt.want)
+			}
+		})
+	}
+}
+
+// testCents is a number of cents, set from a number of dollars by its updater
+type testCents int64
+
+func TestPartialUpdateNumericUpdatersOrder(t *testing.T) {
+	type account struct {
+		Balance testCents `json:"balance"`
+		Count   int       `json:"count"`
+	}
+	centsUpdater := func(fieldValue reflect.Value, v reflect.Value) bool {
+		if fieldValue.Type() != reflect.TypeOf(testCents(0)) || v.Kind() != reflect.Float64 {
+			return false
+		}
+		fieldValue.SetInt(int64(math.Round(v.Float() * 100)))
+		return true
+	}
+
+	// the updaters are tried before the numbers are checked
+	dest := &account{}
+	updaters := []func(reflect.Value, reflect.Value) bool{centsUpdater}
+	if _, err := PartialUpdate(dest, map[string]interface{}{"balance": 12.0}, "json", SkipConditions, updaters); err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if dest.Balance != 1200 {
+		t.Errorf("PartialUpdate() balance = %v, want 1200", dest.Balance)
+	}
+
+	// numbers are only assigned by the updaters, but the reason they are rejected is reported
+	if _, err := PartialUpdate(dest, map[string]interface{}{"count": 2.0}, "json", SkipConditions, nil); err == nil || dest.Count != 0 {
+		t.Errorf("PartialUpdate() error = %v, count = %v, want the value rejected", err, dest.Count)
+	}
+	if _, err := PartialUpdate(dest, map[string]interface{}{"count": 1.5}, "json", SkipConditions, nil); !errors.Is(err, ErrNotIntegral) {
+		t.Errorf("PartialUpdate() error = %v, want %v", err, ErrNotIntegral)
+	}
+}
+
+func TestPartialUpdatePointers(t *testing.T) {
+	type item struct {
+		Name    *string         `json:"name"`
+		Count   *uint           `json:"count"`
+		Status  *testStatus     `json:"status"`
+		Nested  **int           `json:"nested"`
+		Title   *null.String    `json:"title"`
+		Started *time.Time      `json:"started"`
+		Limits  map[string]*int `json:"limits"`
+		Small   *int8           `json:"small"`
+	}
+	tests := []struct {
+		name    string
+		partial map[string]interface{}
+		want    []string
+		wantErr bool
+	}{
+		{name: "string to *string", partial: map[string]interface{}{"name": "John"}, want: []string{"Name"}},
+		{name: "float to *uint", partial: map[string]interface{}{"count": 3.0}, want: []string{"Count"}},
+		{name: "string to pointer to named string", partial: map[string]interface{}{"status": "active"}, want: []string{"Status"}},
+		{name: "float to **int", partial: map[string]interface{}{"nested": 2.0}, want: []string{"Nested"}},
+		{name: "string to *null.String", partial: map[string]interface{}{"title": "Boss"}, want: []string{"Title"}},
+		{name: "string to *time.Time", partial: map[string]interface{}{"started": "2020-01-02T03:04:05Z"}, want: []string{"Started"}},
+		{name: "map to map of pointers", partial: map[string]interface{}{"limits": map[string]interface{}{"a": 1.0}}, want: []string{"Limits"}},
+		{name: "null to pointers", partial: map[string]interface{}{"name": nil, "nested": nil, "title": nil}, want: []string{"Name", "Nested", "Title"}},
+		{name: "negative to *uint", partial: map[string]interface{}{"count": -1.0}, want: []string{}, wantErr: true},
+		{name: "out of range to *int8", partial: map[string]interface{}{"small": 300.0}, want: []string{}, wantErr: true},
+		{name: "bool to *string", partial: map[string]interface{}{"name": true}, want: []string{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, apply := range []func(*item) ([]string, error){
+				func(dest *item) ([]string, error) {
+					return PartialUpdate(dest, tt.partial, "json", SkipConditions, Updaters)
+				},
+				func(dest *item) ([]string, error) {
+					return Apply(dest, tt.partial)
+				},
+			} {
+				dest := &item{}
+				got, err := apply(dest)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("updated = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	one := 1
+	onePtr := &one
+	dest := &item{Nested: &onePtr, Limits: map[string]*int{"a": &one}}
+	if _, err := Apply(dest, map[string]interface{}{"name": "John", "count": 3, "status": "active", "nested": 2, "limits": map[string]interface{}{"b": 2}}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if *dest.Name != "John" || *dest.Count != 3 || *dest.Status != "active" || **dest.Nested != 2 || *dest.Limits["a"] != 1 || *dest.Limits["b"] != 2 {
+		t.Errorf("Apply() dest = %+v", *dest)
+	}
+	// the values previously pointed to are left untouched
+	if one != 1 || *onePtr != 1 {
+		t.Errorf("Apply() modified the previous value to %v", one)
+	}
+
+	// maps pointed to are copied before being merged into
+	m := map[string]string{"a": "1"}
+	withMap := &struct {
+		Meta *map[string]string `json:"meta"`
+	}{Meta: &m}
+	result, err := NewPatcher().Patch(withMap, map[string]interface{}{"meta": map[string]interface{}{"a": "2"}})
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	if m["a"] != "1" || (*withMap.Meta)["a"] != "2" {
+		t.Errorf("Patch() map = %v, dest = %v, want the map untouched", m, *withMap.Meta)
+	}
+	if len(result.Changes) != 1 || !result.Changes[0].Changed {
+		t.Errorf("Patch() changes = %+v, want Meta changed", result.Changes)
+	}
+}
+
+func TestPartialUpdateSQLNull(t *testing.T) {
+	type item struct {
+		Name    sql.NullString   `json:"name"`
+		Total   sql.NullInt64    `json:"total"`
+		Count   sql.NullInt32    `json:"count"`
+		Small   sql.NullInt16    `json:"small"`
+		Level   sql.NullByte     `json:"level"`
+		Price   sql.NullFloat64  `json:"price"`
+		Active  sql.NullBool     `json:"active"`
+		Started sql.NullTime     `json:"started"`
+		Status  sql.Null[string] `json:"status"`
+		Size    sql.Null[uint]   `json:"size"`
+	}
+	started := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	full := item{
+		Name:    sql.NullString{String: "John", Valid: true},
+		Total:   sql.NullInt64{Int64: 1, Valid: true},
+		Count:   sql.NullInt32{Int32: 2, Valid: true},
+		Small:   sql.NullInt16{Int16: 3, Valid: true},
+		Level:   sql.NullByte{Byte: 4, Valid: true},
+		Price:   sql.NullFloat64{Float64: 1.5, Valid: true},
+		Active:  sql.NullBool{Bool: true, Valid: true},
+		Started: sql.NullTime{Time: started, Valid: true},
+		Status:  sql.Null[string]{V: "active", Valid: true},
+		Size:    sql.Null[uint]{V: 5, Valid: true},
+	}
+	tests := []struct {
+		name    string
+		dest    item
+		partial map[string]interface{}
+		want    item
+		wantErr bool
+	}{
+		{
+			name: "values",
+			partial: map[string]interface{}{
+				"name": "John", "total": 1.0, "count": 2.0, "small": 3.0, "level": 4.0, "price": 1.5,
+				"active": true, "started": "2020-01-02T03:04:05Z", "status": "active", "size": 5.0,
+			},
+			want: full,
+		},
+		{
+			name: "widened numbers",
+			partial: map[string]interface{}{
+				"total": int8(1), "count": uint(2), "small": int64(3), "level": 4, "price": 1.5, "size": int16(5),
+			},
+			want: item{Total: full.Total, Count: full.Count, Small: full.Small, Level: full.Level, Price: full.Price, Size: full.Size},
+		},
+		{
+			name: "nulls",
+			dest: full,
+			partial: map[string]interface{}{
+				"name": nil, "total": nil, "count": nil, "small": nil, "level": nil, "price": nil,
+				"active": nil, "started": nil, "status": nil, "size": nil,
+			},
+			want: item{},
+		},
+		{name: "out of range", partial: map[string]interface{}{"small": 70000.0}, wantErr: true},
+		{name: "negative byte", partial: map[string]interface{}{"level": -1.0}, wantErr: true},
+		{name: "not integral", partial: map[string]interface{}{"count": 1.5}, wantErr: true},
+		{name: "wrong type", partial: map[string]interface{}{"name": 1.0}, wantErr: true},
+		{name: "invalid time", partial: map[string]interface{}{"started": "yesterday"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, apply := range []func(*item) ([]string, error){
+				func(dest *item) ([]string, error) {
+					return PartialUpdate(dest, tt.partial, "json", SkipConditions, Updaters)
+				},
+				func(dest *item) ([]string, error) {
+					return Apply(dest, tt.partial)
+				},
+			} {
+				dest := tt.dest
+				_, err := apply(&dest)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if dest != tt.want {
+					t.Errorf("dest = %+v, want %+v", dest, tt.want)
+				}
+			}
+		})
+	}
+
+	// out of range numbers are rejected with the same error as for null.Int
+	dest := &item{}
+	if _, err := Apply(dest, map[string]interface{}{"small": 70000.0}); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Apply() error = %v, want %v", err, ErrOutOfRange)
+	}
+
+	// map elements are converted the same way
+	meta := map[string]sql.NullInt32{}
+	if _, err := PartialUpdate(&struct {
+		Meta map[string]sql.NullInt32 `json:"meta"`
+	}{Meta: meta}, map[string]interface{}{"meta": map[string]interface{}{"a": 1.0}}, "json", SkipConditions, Updaters); err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if meta["a"] != (sql.NullInt32{Int32: 1, Valid: true}) {
+		t.Errorf("PartialUpdate() meta = %v", meta)
+	}
+}
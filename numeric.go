@@ -0,0 +1,137 @@
+package gopartial
+
+import (
+	"errors"
+	"math"
+	"reflect"
+)
+
+var (
+	// ErrOutOfRange rejects a number that doesn't fit in the field type
+	ErrOutOfRange = errors.New("Value out of range")
+	// ErrNotIntegral rejects a float with a fractional part for an integer field,
+	// unless floats are truncated (see WithFloatTruncation)
+	ErrNotIntegral = errors.New("Value is not an integer")
+	// ErrNotFinite rejects NaN and infinite floats
+	ErrNotFinite = errors.New("Value is not a finite number")
+)
+
+// intValue returns the int, uint or float v as an int64 that fits in an integer of size bits.
+// It returns errNotHandled when v is not a number.
+func intValue(v reflect.Value, bits int) (int64, error) {
+	if !v.IsValid() {
+		return 0, errNotHandled
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if bits < 64 && (i < -1<<(bits-1) || i > 1<<(bits-1)-1) {
+			return 0, ErrOutOfRange
+		}
+		return i, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > 1<<(bits-1)-1 {
+			return 0, ErrOutOfRange
+		}
+		return int64(u), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, ErrNotFinite
+		}
+		if f != math.Trunc(f) {
+			return 0, ErrNotIntegral
+		}
+		if f < -math.Ldexp(1, bits-1) || f >= math.Ldexp(1, bits-1) {
+			return 0, ErrOutOfRange
+		}
+		return int64(f), nil
+	}
+
+	return 0, errNotHandled
+}
+
+// floatValue returns the int, uint or float v as a float64 that fits in a float of size bits.
+// It returns errNotHandled when v is not a number.
+func floatValue(v reflect.Value, bits int) (float64, error) {
+	if !v.IsValid() {
+		return 0, errNotHandled
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, ErrNotFinite
+		}
+		if bits == 32 && math.Abs(f) > math.MaxFloat32 {
+			return 0, ErrOutOfRange
+		}
+		return f, nil
+	}
+
+	return 0, errNotHandled
+}
+
+// uintValue returns the int, uint or float v as a uint64 that fits in an unsigned integer of
+// size bits. It returns errNotHandled when v is not a number.
+func uintValue(v reflect.Value, bits int) (uint64, error) {
+	if !v.IsValid() {
+		return 0, errNotHandled
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if i < 0 || (bits < 64 && uint64(i) > 1<<bits-1) {
+			return 0, ErrOutOfRange
+		}
+		return uint64(i), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if bits < 64 && u > 1<<bits-1 {
+			return 0, ErrOutOfRange
+		}
+		return u, nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, ErrNotFinite
+		}
+		if f != math.Trunc(f) {
+			return 0, ErrNotIntegral
+		}
+		if f < 0 || f >= math.Ldexp(1, bits) {
+			return 0, ErrOutOfRange
+		}
+		return uint64(f), nil
+	}
+
+	return 0, errNotHandled
+}
+
+// isFinite returns whether v is not a NaN or infinite float
+func isFinite(v reflect.Value) bool {
+	if !v.IsValid() || (v.Kind() != reflect.Float32 && v.Kind() != reflect.Float64) {
+		return true
+	}
+	return !math.IsNaN(v.Float()) && !math.IsInf(v.Float(), 0)
+}
+
+// truncated returns v truncated to an integer when it is a float with a fractional part
+func truncated(v reflect.Value) (reflect.Value, bool) {
+	if !v.IsValid() || (v.Kind() != reflect.Float32 && v.Kind() != reflect.Float64) {
+		return v, false
+	}
+	f := v.Float()
+	if math.IsNaN(f) || math.IsInf(f, 0) || f == math.Trunc(f) {
+		return v, false
+	}
+	return reflect.ValueOf(math.Trunc(f)).Convert(v.Type()), true
+}
@@ -0,0 +1,163 @@
+package gopartial
+
+import "reflect"
+
+// defaultTagName is the struct tag name used when none is given
+const defaultTagName = "json"
+
+// Option configures how a partial is applied to a struct
+type Option func(*options)
+
+// options is the configuration a partial is applied with
+type options struct {
+	tagName        string
+	skipConditions []func(reflect.StructField) bool
+	updaters       []func(reflect.Value, reflect.Value) bool
+	// registry looks up the updater of each field type, updaters are used when it is nil
+	registry *Registry
+	// converters are the converters by name, used for the fields tagged `partial:"conv=name"`
+	converters map[string]converter
+
+	// caseInsensitive matches partial keys with field names regardless of their case
+	caseInsensitive bool
+	// fieldNameFallback matches partial keys with the Go field name of tagged fields
+	fieldNameFallback bool
+	// strict rejects the partial keys that match no field
+	strict bool
+	// atomic only writes to dest when every value could be assigned
+	atomic bool
+	// failFast stops on the first value that could not be assigned
+	failFast bool
+	// changedOnly only reports the fields whose value actually changed
+	changedOnly bool
+	// truncateFloats truncates the floats with a fractional part that integer fields reject
+	truncateFloats bool
+}
+
+// newOptions returns the options for the given tag name, skip conditions and updaters or registry
+// with opts applied
+func newOptions(tagName string, skipConditions []func(reflect.StructField) bool, updaters []func(reflect.Value, reflect.Value) bool, registry *Registry, opts []Option) *options {
+	o := &options{
+		tagName:        tagName,
+		skipConditions: skipConditions,
+		updaters:       updaters,
+		registry:       registry,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithTag matches partial keys with the struct tag tagName (e.g. "json" or "db")
+func WithTag(tagName string) Option {
+	return func(o *options) {
+		o.tagName = tagName
+	}
+}
+
+// WithSkipConditions replaces the skip conditions fields are checked against
+func WithSkipConditions(skipConditions ...func(reflect.StructField) bool) Option {
+	return func(o *options) {
+		o.skipConditions = skipConditions
+	}
+}
+
+// WithUpdaters replaces the updaters used to assign the partial values, which are tried one
+// after the other instead of being looked up in a registry
+func WithUpdaters(updaters ...func(reflect.Value, reflect.Value) bool) Option {
+	return func(o *options) {
+		o.updaters = updaters
+		o.registry = nil
+	}
+}
+
+// WithRegistry looks up the updater of each field in registry instead of trying updaters
+// one after the other
+func WithRegistry(registry *Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+		o.updaters = nil
+	}
+}
+
+// WithCaseInsensitiveKeys matches partial keys with the field names regardless of their case
+// (e.g. "FirstName" with "firstname") like encoding/json does. An exact match is always preferred.
+func WithCaseInsensitiveKeys() Option {
+	return func(o *options) {
+		o.caseInsensitive = true
+	}
+}
+
+// WithFieldNameFallback matches partial keys with the Go field name of tagged fields as well
+// (e.g. "FirstName" with a field tagged `json:"first_name"`). A match on the tag name is always preferred.
+func WithFieldNameFallback() Option {
+	return func(o *options) {
+		o.fieldNameFallback = true
+	}
+}
+
+// WithStrict rejects the partial keys that match no struct field, including nested ones, like
+// json.Decoder.DisallowUnknownFields does: each of them is returned as a FieldError. Without
+// it, unknown keys are ignored and only listed in Result.UnknownKeys.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithAtomic applies the partial all or nothing: dest is only written to when every value of the
+// partial could be assigned, otherwise it is left untouched and no field is reported as updated.
+// Structs, slices and maps reached through pointers are copied before being updated, so that
+// dest never shares a half-updated value.
+func WithAtomic() Option {
+	return func(o *options) {
+		o.atomic = true
+	}
+}
+
+// WithFailFast stops the update on the first value that could not be assigned, and returns
+// only its error instead of collecting the errors of all the values.
+func WithFailFast() Option {
+	return func(o *options) {
+		o.failFast = true
+	}
+}
+
+// WithChangedOnly only reports the fields whose value actually changed as updated, so that
+// fields set to their current value are left out. Values are compared with their Equal
+// method when they have one (e.g. time.Time or null.Time), and with reflect.DeepEqual otherwise.
+func WithChangedOnly() Option {
+	return func(o *options) {
+		o.changedOnly = true
+	}
+}
+
+// WithFloatTruncation truncates the floats with a fractional part for the integer fields, e.g. 1.9
+// is assigned as 1, instead of rejecting them with ErrNotIntegral
+func WithFloatTruncation() Option {
+	return func(o *options) {
+		o.truncateFloats = true
+	}
+}
+
+// WithConverter uses updater for the fields tagged `partial:"conv=name"`, instead of the updaters
+// of their type
+func WithConverter(name string, updater func(reflect.Value, reflect.Value) bool) Option {
+	return withConverter(name, updaterConverter(updater))
+}
+
+// WithConverterFunc uses fn for the fields tagged `partial:"conv=name"` of type To or *To, instead of
+// the updaters of their type. Values are converted like RegisterConverter does.
+func WithConverterFunc[From any, To any](name string, fn func(From) (To, error)) Option {
+	return withConverter(name, newConverter(fn))
+}
+
+func withConverter(name string, c converter) Option {
+	return func(o *options) {
+		if o.converters == nil {
+			o.converters = make(map[string]converter)
+		}
+		o.converters[name] = c
+	}
+}
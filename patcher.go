@@ -0,0 +1,57 @@
+package gopartial
+
+import "reflect"
+
+// Patcher applies partials to structs with the configuration it was built with.
+// It is immutable and safe for concurrent use, so a service can configure it once
+// and share it between all of its handlers.
+type Patcher struct {
+	options *options
+}
+
+// NewPatcher returns a Patcher configured with opts. Without options, it matches
+// partial keys with the "json" struct tag and uses SkipConditions and DefaultRegistry.
+// The Patcher keeps a copy of the registry, so updaters registered later on are not used by it.
+func NewPatcher(opts ...Option) *Patcher {
+	o := newOptions(defaultTagName, SkipConditions, nil, DefaultRegistry, opts)
+
+	// keep copies of the slices and registry so that the patcher can't be changed through them
+	o.skipConditions = append([]func(reflect.StructField) bool(nil), o.skipConditions...)
+	o.updaters = append([]func(reflect.Value, reflect.Value) bool(nil), o.updaters...)
+	if o.registry != nil {
+		o.registry = o.registry.Clone()
+	}
+
+	return &Patcher{options: o}
+}
+
+// Apply updates dest (Must be a pointer to a struct) from partial like PartialUpdate
+// does and returns the list of struct field names that were successfully updated.
+func (p *Patcher) Apply(dest interface{}, partial map[string]interface{}) ([]string, error) {
+	return updated(p.apply(dest, partial, modeUpdate))
+}
+
+// Patch updates dest (Must be a pointer to a struct) from partial like Apply does and
+// returns a Result describing the update: the old and new value of each updated field,
+// and the partial keys that matched no field.
+func (p *Patcher) Patch(dest interface{}, partial map[string]interface{}) (*Result, error) {
+	return p.apply(dest, partial, modeDescribe)
+}
+
+// Preview computes what Patch would do to dest (Must be a pointer to a struct) with partial,
+// with the same skip conditions and updaters, but without modifying dest. The returned Result
+// describes the old and new value of each field that would be updated, and the returned error
+// the values that would be rejected.
+func (p *Patcher) Preview(dest interface{}, partial map[string]interface{}) (*Result, error) {
+	return p.apply(dest, partial, modePreview)
+}
+
+func (p *Patcher) apply(dest interface{}, partial map[string]interface{}, m mode) (*Result, error) {
+	valueOfDest := reflect.ValueOf(dest)
+	// Must be a pointer to a struct so that it can be updated
+	if valueOfDest.Kind() != reflect.Ptr {
+		return nil, errDestinationMustBePointerType
+	}
+
+	return apply(valueOfDest.Elem(), partial, p.options, m)
+}
@@ -0,0 +1,473 @@
+package gopartial
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+func TestPatcher(t *testing.T) {
+	type user struct {
+		ID    string `json:"id" db:"id" props:"readonly"`
+		Name  string `json:"name" db:"user_name"`
+		Email string `json:"email" db:"email" props:"internal"`
+	}
+
+	skipInternal := func(field reflect.StructField) bool {
+		return strings.Contains(field.Tag.Get("props"), "internal")
+	}
+	skipConditions := []func(reflect.StructField) bool{skipInternal}
+
+	tests := []struct {
+		name    string
+		patcher *Patcher
+		dest    interface{}
+		partial map[string]interface{}
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			patcher: NewPatcher(),
+			dest:    &user{},
+			partial: map[string]interface{}{"id": "1", "name": "John", "email": "john@example.com"},
+			want:    []string{"Name", "Email"},
+		},
+		{
+			name:    "tag and skip conditions",
+			patcher: NewPatcher(WithTag("db"), WithSkipConditions(skipConditions...)),
+			dest:    &user{},
+			partial: map[string]interface{}{"id": "1", "user_name": "John", "email": "john@example.com"},
+			want:    []string{"ID", "Name"},
+		},
+		{
+			name:    "no updaters",
+			patcher: NewPatcher(WithUpdaters()),
+			dest:    &user{},
+			partial: map[string]interface{}{"name": 1},
+			want:    []string{},
+			wantErr: true,
+		},
+		{
+			name:    "case insensitive",
+			patcher: NewPatcher(WithCaseInsensitiveKeys()),
+			dest:    &user{},
+			partial: map[string]interface{}{"NAME": "John"},
+			want:    []string{"Name"},
+		},
+		{
+			name:    "non pointer dest",
+			patcher: NewPatcher(),
+			dest:    user{},
+			partial: map[string]interface{}{},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.patcher.Apply(tt.dest, tt.partial)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Patcher.Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Patcher.Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// changing the slices a patcher was built with doesn't change the patcher
+	patcher := NewPatcher(WithSkipConditions(skipConditions...))
+	skipConditions[0] = func(reflect.StructField) bool { return true }
+	got, err := patcher.Apply(&user{}, map[string]interface{}{"name": "John"})
+	if err != nil || !reflect.DeepEqual(got, []string{"Name"}) {
+		t.Errorf("Patcher.Apply() = %v, %v, want [Name]", got, err)
+	}
+}
+
+func TestPatcherConcurrent(t *testing.T) {
+	patcher := NewPatcher(WithCaseInsensitiveKeys())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				dest := &benchUser{}
+				if _, err := patcher.Apply(dest, benchPartial()); err != nil {
+					t.Error(err)
+					return
+				}
+				if dest.Name != "John" || dest.Address.City != "Paris" {
+					t.Errorf("Patcher.Apply() dest = %+v", dest)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPatcherUnknownKeys(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type user struct {
+		Email   string  `json:"email"`
+		Secret  string  `json:"-"`
+		Address address `json:"address"`
+	}
+	partial := map[string]interface{}{
+		"emial":  "john@example.com",
+		"Secret": "x",
+		"address": map[string]interface{}{
+			"city": "Paris",
+			"zip":  "75001",
+		},
+	}
+
+	result, err := NewPatcher().Patch(&user{}, partial)
+	if err != nil {
+		t.Fatalf("Patcher.Patch() error = %v", err)
+	}
+	if want := []string{"Address.City"}; !reflect.DeepEqual(result.Updated, want) {
+		t.Errorf("Patcher.Patch() updated = %v, want %v", result.Updated, want)
+	}
+	if want := []string{"Secret", "emial", "address.zip"}; !reflect.DeepEqual(result.UnknownKeys, want) {
+		t.Errorf("Patcher.Patch() unknown keys = %v, want %v", result.UnknownKeys, want)
+	}
+
+	result, err = NewPatcher(WithStrict()).Patch(&user{}, partial)
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) {
+		t.Fatalf("Patcher.Patch() error = %v, want *PatchError", err)
+	}
+	want := []FieldError{
+		{Path: "Secret", Key: "Secret", Received: "string", Reason: reasonUnknownKey},
+		{Path: "emial", Key: "emial", Received: "string", Reason: reasonUnknownKey},
+		{Path: "Address.zip", Key: "address.zip", Received: "string", Reason: reasonUnknownKey},
+	}
+	if !reflect.DeepEqual(patchErr.Fields, want) {
+		t.Errorf("Patcher.Patch() field errors = %+v, want %+v", patchErr.Fields, want)
+	}
+	if got, want := patchErr.Fields[1].Error(), "emial: unknown key (received string)"; got != want {
+		t.Errorf("FieldError.Error() = %q, want %q", got, want)
+	}
+	if want := []string{"Address.City"}; !reflect.DeepEqual(result.Updated, want) {
+		t.Errorf("Patcher.Patch() updated = %v, want %v", result.Updated, want)
+	}
+}
+
+func TestPatcherAtomic(t *testing.T) {
+	type Base struct {
+		Version int `json:"version"`
+	}
+	type address struct {
+		City string `json:"city"`
+	}
+	type item struct {
+		Qty int `json:"qty"`
+	}
+	type order struct {
+		*Base
+		Name     string            `json:"name"`
+		Shipping *address          `json:"shipping"`
+		Items    []item            `json:"items"`
+		Meta     map[string]string `json:"meta"`
+		Total    float64           `json:"total"`
+	}
+	newOrder := func() *order {
+		return &order{
+			Base:     &Base{Version: 1},
+			Name:     "order",
+			Shipping: &address{City: "Toronto"},
+			Items:    []item{{Qty: 1}},
+			Meta:     map[string]string{"color": "red"},
+			Total:    10,
+		}
+	}
+	partial := map[string]interface{}{
+		"version":  2,
+		"name":     "updated",
+		"shipping": map[string]interface{}{"city": "Paris"},
+		"items":    map[string]interface{}{"0": map[string]interface{}{"qty": 2}},
+		"meta":     map[string]interface{}{"color": "blue"},
+		"total":    "ten",
+	}
+
+	dest := newOrder()
+	shipping, items, meta := dest.Shipping, dest.Items, dest.Meta
+	result, err := NewPatcher(WithAtomic()).Patch(dest, partial)
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) || len(patchErr.Fields) != 1 || patchErr.Fields[0].Path != "Total" {
+		t.Fatalf("Patcher.Patch() error = %v, want Total error", err)
+	}
+	if len(result.Updated) != 0 {
+		t.Errorf("Patcher.Patch() updated = %v, want none", result.Updated)
+	}
+	if !reflect.DeepEqual(dest, newOrder()) || dest.Shipping != shipping || &dest.Items[0] != &items[0] {
+		t.Errorf("Patcher.Patch() dest = %+v, want it untouched", dest)
+	}
+	if !reflect.DeepEqual(meta, map[string]string{"color": "red"}) {
+		t.Errorf("Patcher.Patch() meta = %v, want it untouched", meta)
+	}
+
+	// all the values are written once all of them could be assigned
+	partial["total"] = 20
+	result, err = NewPatcher(WithAtomic()).Patch(dest, partial)
+	if err != nil {
+		t.Fatalf("Patcher.Patch() error = %v", err)
+	}
+	if want := []string{"Version", "Name", "Shipping.City", "Items.0.Qty", "Meta", "Total"}; !reflect.DeepEqual(result.Updated, want) {
+		t.Errorf("Patcher.Patch() updated = %v, want %v", result.Updated, want)
+	}
+	if dest.Version != 2 || dest.Name != "updated" || dest.Shipping.City != "Paris" || dest.Items[0].Qty != 2 || dest.Meta["color"] != "blue" || dest.Total != 20 {
+		t.Errorf("Patcher.Patch() dest = %+v", dest)
+	}
+}
+
+func TestPatcherFailFast(t *testing.T) {
+	type user struct {
+		Name  string  `json:"name"`
+		Age   int     `json:"age"`
+		Score float64 `json:"score"`
+	}
+	partial := map[string]interface{}{"name": 1, "age": "x", "score": "y", "unknown": true}
+
+	_, err := NewPatcher(WithStrict()).Apply(&user{}, partial)
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) || len(patchErr.Fields) != 4 {
+		t.Fatalf("Patcher.Apply() error = %v, want 4 field errors", err)
+	}
+
+	_, err = NewPatcher(WithStrict(), WithFailFast()).Apply(&user{}, partial)
+	if !errors.As(err, &patchErr) || len(patchErr.Fields) != 1 || patchErr.Fields[0].Key != "unknown" {
+		t.Fatalf("Patcher.Apply() error = %v, want the unknown key error only", err)
+	}
+}
+
+func TestPatcherPreview(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type user struct {
+		Name    string            `json:"name"`
+		Age     int               `json:"age"`
+		Address *address          `json:"address"`
+		Meta    map[string]string `json:"meta"`
+	}
+	newUser := func() *user {
+		return &user{Name: "John", Age: 30, Meta: map[string]string{"color": "red"}}
+	}
+	partial := map[string]interface{}{
+		"name":    "Jane",
+		"age":     "old",
+		"address": map[string]interface{}{"city": "Paris"},
+		"meta":    map[string]interface{}{"color": "blue"},
+		"emial":   "jane@example.com",
+	}
+
+	dest := newUser()
+	meta := dest.Meta
+	result, err := NewPatcher().Preview(dest, partial)
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) || len(patchErr.Fields) != 1 || patchErr.Fields[0].Path != "Age" {
+		t.Fatalf("Patcher.Preview() error = %v, want Age error", err)
+	}
+	if !reflect.DeepEqual(dest, newUser()) || !reflect.DeepEqual(meta, map[string]string{"color": "red"}) {
+		t.Errorf("Patcher.Preview() dest = %+v, want it untouched", dest)
+	}
+	if want := []string{"Name", "Address", "Address.City", "Meta"}; !reflect.DeepEqual(result.Updated, want) {
+		t.Errorf("Patcher.Preview() updated = %v, want %v", result.Updated, want)
+	}
+	if want := []string{"emial"}; !reflect.DeepEqual(result.UnknownKeys, want) {
+		t.Errorf("Patcher.Preview() unknown keys = %v, want %v", result.UnknownKeys, want)
+	}
+	want := []Change{
+		{Name: "Name", Key: "name", Path: "Name", Old: "John", New: "Jane", Changed: true},
+		{Name: "Address", Key: "address", Path: "Address", Old: (*address)(nil), New: &address{City: "Paris"}, Changed: true},
+		{Name: "City", Key: "address.city", Path: "Address.City", Old: "", New: "Paris", Changed: true},
+		{Name: "Meta", Key: "meta", Path: "Meta", Old: map[string]string{"color": "red"}, New: map[string]string{"color": "blue"}, Changed: true},
+	}
+	if !reflect.DeepEqual(result.Changes, want) {
+		t.Errorf("Patcher.Preview() changes = %+v, want %+v", result.Changes, want)
+	}
+
+	// a preview in atomic mode reports no change when a value is rejected
+	result, _ = NewPatcher(WithAtomic()).Preview(dest, partial)
+	if len(result.Updated) != 0 || len(result.Changes) != 0 {
+		t.Errorf("Patcher.Preview() changes = %+v, want none", result.Changes)
+	}
+}
+
+func TestPatcherChanges(t *testing.T) {
+	type item struct {
+		Qty int `json:"qty"`
+	}
+	type order struct {
+		Name  string `json:"name"`
+		Total int    `json:"total"`
+		Items []item `json:"items"`
+	}
+	dest := &order{Name: "order", Total: 10, Items: []item{{Qty: 1}}}
+	partial := map[string]interface{}{
+		"name":    "order",
+		"total":   20,
+		"items.0": map[string]interface{}{"qty": 2},
+	}
+
+	result, err := NewPatcher().Patch(dest, partial)
+	if err != nil {
+		t.Fatalf("Patcher.Patch() error = %v", err)
+	}
+	want := []Change{
+		{Name: "Name", Key: "name", Path: "Name", Old: "order", New: "order", Changed: false},
+		{Name: "Total", Key: "total", Path: "Total", Old: 10, New: 20, Changed: true},
+		{Name: "Qty", Key: "items.0.qty", Path: "Items.0.Qty", Old: 1, New: 2, Changed: true},
+	}
+	if !reflect.DeepEqual(result.Changes, want) {
+		t.Errorf("Patcher.Patch() changes = %+v, want %+v", result.Changes, want)
+	}
+	if dest.Total != 20 || dest.Items[0].Qty != 2 {
+		t.Errorf("Patcher.Patch() dest = %+v", dest)
+	}
+}
+
+func TestPatcherChangedOnly(t *testing.T) {
+	now := time.Now()
+	type user struct {
+		Name      string            `json:"name"`
+		Age       int               `json:"age"`
+		Nickname  *string           `json:"nickname"`
+		Tags      []string          `json:"tags"`
+		Meta      map[string]string `json:"meta"`
+		Birthday  time.Time         `json:"birthday"`
+		LastLogin null.Time         `json:"last_login"`
+	}
+	nickname := "jd"
+	dest := &user{
+		Name:      "John",
+		Age:       30,
+		Nickname:  &nickname,
+		Tags:      []string{"admin"},
+		Meta:      map[string]string{"color": "red"},
+		Birthday:  now,
+		LastLogin: null.TimeFrom(now),
+	}
+	sameNickname := "jd"
+	partial := map[string]interface{}{
+		"name":     "John",
+		"age":      31,
+		"nickname": &sameNickname,
+		"tags":     []string{"admin"},
+		"meta":     map[string]interface{}{"color": "red"},
+		// the monotonic clock reading is stripped, so DeepEqual would report a change
+		"birthday":   now.Round(0),
+		"last_login": null.TimeFrom(now.Round(0)),
+	}
+
+	result, err := NewPatcher(WithChangedOnly()).Patch(dest, partial)
+	if err != nil {
+		t.Fatalf("Patcher.Patch() error = %v", err)
+	}
+	if want := []string{"Age"}; !reflect.DeepEqual(result.Updated, want) {
+		t.Errorf("Patcher.Patch() updated = %v, want %v", result.Updated, want)
+	}
+	if len(result.Changes) != 7 {
+		t.Fatalf("Patcher.Patch() changes = %+v, want 7 changes", result.Changes)
+	}
+	for _, change := range result.Changes {
+		if change.Changed != (change.Path == "Age") {
+			t.Errorf("Patcher.Patch() change %v changed = %v", change.Path, change.Changed)
+		}
+	}
+
+	// without the option every assigned field is reported as updated
+	updated, err := NewPatcher().Apply(dest, partial)
+	if err != nil {
+		t.Fatalf("Patcher.Apply() error = %v", err)
+	}
+	if len(updated) != 7 {
+		t.Errorf("Patcher.Apply() updated = %v, want all the fields", updated)
+	}
+}
+
+func TestPatcherConverters(t *testing.T) {
+	type contact struct {
+		Phone   string  `json:"phone" partial:"conv=e164"`
+		Balance int64   `json:"balance" partial:"conv=cents"`
+		Credit  *int64  `json:"credit" partial:"conv=cents"`
+		Fax     string  `json:"fax" partial:"conv=fax"`
+		Score   float64 `json:"score"`
+	}
+	errCents := errors.New("Invalid amount")
+	patcher := NewPatcher(
+		WithConverter("e164", func(fieldValue reflect.Value, v reflect.Value) bool {
+			if v.Kind() != reflect.String {
+				return false
+			}
+			digits := strings.Map(func(r rune) rune {
+				if r < '0' || r > '9' {
+					return -1
+				}
+				return r
+			}, v.String())
+			fieldValue.SetString("+" + digits)
+			return true
+		}),
+		WithConverterFunc("cents", func(s string) (int64, error) {
+			var units, cents int64
+			if _, err := fmt.Sscanf(s, "%d.%02d", &units, &cents); err != nil {
+				return 0, errCents
+			}
+			return units*100 + cents, nil
+		}),
+	)
+
+	dest := &contact{}
+	updated, err := patcher.Apply(dest, map[string]interface{}{
+		"phone":   "1 (416) 555-0100",
+		"balance": "12.34",
+		"credit":  "0.50",
+		"score":   2,
+	})
+	if err != nil {
+		t.Fatalf("Patcher.Apply() error = %v", err)
+	}
+	if want := []string{"Phone", "Balance", "Credit", "Score"}; !reflect.DeepEqual(updated, want) {
+		t.Errorf("Patcher.Apply() updated = %v, want %v", updated, want)
+	}
+	if dest.Phone != "+14165550100" || dest.Balance != 1234 || dest.Credit == nil || *dest.Credit != 50 || dest.Score != 2 {
+		t.Errorf("Patcher.Apply() dest = %+v", dest)
+	}
+
+	// the converter replaces the updaters of the field type, which would accept a number
+	_, err = patcher.Apply(dest, map[string]interface{}{"balance": 12, "credit": "ten", "fax": "555-0101", "phone": nil})
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) || len(patchErr.Fields) != 4 {
+		t.Fatalf("Patcher.Apply() error = %v, want 4 field errors", err)
+	}
+	wantReasons := map[string]string{
+		"Phone":   reasonIncompatible,
+		"Balance": reasonIncompatible,
+		"Credit":  errCents.Error(),
+		"Fax":     `unknown converter "fax"`,
+	}
+	for _, fieldErr := range patchErr.Fields {
+		if fieldErr.Reason != wantReasons[fieldErr.Path] {
+			t.Errorf("Patcher.Apply() %v error = %v, want %v", fieldErr.Path, fieldErr.Reason, wantReasons[fieldErr.Path])
+		}
+	}
+	if !errors.Is(err, errCents) {
+		t.Errorf("Patcher.Apply() error = %v, want %v", err, errCents)
+	}
+}
@@ -0,0 +1,85 @@
+package gopartial
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// pathSeparator separates the levels of a dot-path key, e.g. "address.city"
+const pathSeparator = "."
+
+// pathConflict is the error of a dot-path key addressing a value also given by another key
+type pathConflict struct {
+	// path is the dot-path key
+	path string
+	// source is the key it conflicts with
+	source string
+}
+
+// Error implements the error interface
+func (e *pathConflict) Error() string {
+	return fmt.Sprintf("Partial key %q conflicts with key %q", e.path, e.source)
+}
+
+// expandPaths expands the dot-path keys of partial (e.g. "address.city" or "items.0.qty")
+// into nested maps so that they are applied like nested objects. Keys for which isKey
+// returns true are kept as they are. A dot-path key addressing a value that is also given
+// by another key (e.g. both "address.city" and "address") is a conflict.
+func expandPaths(partial map[string]interface{}, isKey func(string) bool) (map[string]interface{}, error) {
+	paths := make([]string, 0)
+	for key := range partial {
+		if strings.Contains(key, pathSeparator) && !isKey(key) {
+			paths = append(paths, key)
+		}
+	}
+	if len(paths) == 0 {
+		return partial, nil
+	}
+	// expand in order so that conflicts are reported consistently
+	sort.Strings(paths)
+
+	expanded := make(map[string]interface{}, len(partial))
+	for key, val := range partial {
+		expanded[key] = val
+	}
+	for _, path := range paths {
+		delete(expanded, path)
+	}
+
+	// sources keeps track of the dot-path key that set each expanded path, and
+	// created of the nested maps created while expanding: only those can be shared
+	// between dot-path keys
+	sources := make(map[string]string)
+	created := make(map[string]bool)
+	for _, path := range paths {
+		segments := strings.Split(path, pathSeparator)
+		nested := expanded
+		for i, segment := range segments {
+			prefix := strings.Join(segments[:i+1], pathSeparator)
+			existing, ok := nested[segment]
+			if ok && (i == len(segments)-1 || !created[prefix]) {
+				source, isPath := sources[prefix]
+				if !isPath {
+					source = prefix
+				}
+				return nil, &pathConflict{path: path, source: source}
+			}
+
+			if i == len(segments)-1 {
+				nested[segment] = partial[path]
+				sources[prefix] = path
+			} else if ok {
+				nested = existing.(map[string]interface{})
+			} else {
+				child := make(map[string]interface{})
+				nested[segment] = child
+				nested = child
+				sources[prefix] = path
+				created[prefix] = true
+			}
+		}
+	}
+
+	return expanded, nil
+}
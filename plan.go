@@ -0,0 +1,150 @@
+package gopartial
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// maxCachedSlices bounds how many skip conditions slices have their decisions cached per
+// struct plan, so that slices built on every call can't grow the cache forever
+const maxCachedSlices = 64
+
+// plans caches the compiled struct plans by planKey
+var plans sync.Map
+
+// planKey identifies the plan of a struct type for a tag name
+type planKey struct {
+	typ     reflect.Type
+	tagName string
+}
+
+// structPlan is the compiled plan to update a struct type for a tag name: its fields with their
+// index sequences and parsed tags, and the fields skipped by each skip conditions slice.
+// It is compiled once and safe for concurrent use.
+type structPlan struct {
+	fields []field
+	// byName is the position in fields of each field name
+	byName map[string]int
+
+	// skips caches the *skipDecisions by the sliceID of the skip conditions
+	skips      sync.Map
+	skipsCount int32
+}
+
+// skipDecisions are the fields skipped by a skip conditions slice, by position in the plan fields
+type skipDecisions struct {
+	// skipConditions is a copy of the slice, which keeps its functions alive and tells whether
+	// the slice still holds them
+	skipConditions []func(reflect.StructField) bool
+	skip           []bool
+}
+
+// sliceID identifies a slice by its backing array and length
+type sliceID struct {
+	ptr uintptr
+	len int
+}
+
+// identify returns the sliceID of the skip conditions slice
+func identify(slice []func(reflect.StructField) bool) sliceID {
+	return sliceID{ptr: uintptr(unsafe.Pointer(unsafe.SliceData(slice))), len: len(slice)}
+}
+
+// planFor returns the plan of the struct type t for tagName, compiling it on first use
+func planFor(t reflect.Type, tagName string) *structPlan {
+	key := planKey{typ: t, tagName: tagName}
+	if p, ok := plans.Load(key); ok {
+		return p.(*structPlan)
+	}
+
+	fields := structFields(t, tagName)
+	p := &structPlan{
+		fields: fields,
+		byName: make(map[string]int, len(fields)),
+	}
+	for i, field := range fields {
+		p.byName[field.name] = i
+	}
+
+	actual, _ := plans.LoadOrStore(key, p)
+	return actual.(*structPlan)
+}
+
+// skipped returns whether each of the plan fields is skipped by skipConditions. The decisions
+// are cached by the identity of the slice, and only used as long as it holds the same functions,
+// so that a slice modified in place is run again.
+func (p *structPlan) skipped(skipConditions []func(reflect.StructField) bool) []bool {
+	id := identify(skipConditions)
+	if d, ok := p.skips.Load(id); ok && d.(*skipDecisions).holds(skipConditions) {
+		return d.(*skipDecisions).skip
+	}
+
+	skip := make([]bool, len(p.fields))
+	for i, field := range p.fields {
+		skip[i] = skipField(field, skipConditions)
+	}
+
+	decisions := &skipDecisions{skipConditions: append([]func(reflect.StructField) bool(nil), skipConditions...), skip: skip}
+	if _, ok := p.skips.Load(id); ok {
+		p.skips.Store(id, decisions)
+	} else if atomic.LoadInt32(&p.skipsCount) < maxCachedSlices {
+		if _, loaded := p.skips.LoadOrStore(id, decisions); !loaded {
+			atomic.AddInt32(&p.skipsCount, 1)
+		}
+	}
+	return skip
+}
+
+// holds returns whether skipConditions holds the same functions as the ones the decisions were made with
+func (d *skipDecisions) holds(skipConditions []func(reflect.StructField) bool) bool {
+	if len(d.skipConditions) != len(skipConditions) {
+		return false
+	}
+	for i, skipCondition := range skipConditions {
+		if funcPointer(skipCondition) != funcPointer(d.skipConditions[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// funcPointer returns the pointer to the closure of f, which differs between two closures of the
+// same function unlike reflect.Value.Pointer. The skip decisions keep their functions alive, so
+// that the pointer can't be reused by another closure.
+func funcPointer(f func(reflect.StructField) bool) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&f))
+}
+
+// mapUpdater and sqlNullUpdater identify MapStringInterfaceUpdater and SQLNullUpdater in the updaters
+var (
+	mapUpdater     = reflect.ValueOf(MapStringInterfaceUpdater).Pointer()
+	sqlNullUpdater = reflect.ValueOf(SQLNullUpdater).Pointer()
+)
+
+// update assigns v to fieldValue with the first of the updaters that succeeds, in order.
+// MapStringInterfaceUpdater and SQLNullUpdater convert the values they hold like fields,
+// so with the updaters in use rather than Updaters.
+func (p *patch) update(fieldValue reflect.Value, v reflect.Value) bool {
+	// go through all extended process types
+	for _, updater := range p.updaters {
+		switch reflect.ValueOf(updater).Pointer() {
+		case mapUpdater:
+			if fieldValue.Kind() == reflect.Map && mergeMap(fieldValue, v, p.assigned) {
+				return true
+			}
+		case sqlNullUpdater:
+			if isSQLNull(fieldValue.Type()) && assignSQLNull(fieldValue, v, p.assign) == nil {
+				return true
+			}
+		default:
+			if updater(fieldValue, v) {
+				// the first updateSuccess found, break the loop
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,259 @@
+package gopartial
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+type benchAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type benchUser struct {
+	ID        string       `json:"id" props:"readonly"`
+	Name      string       `json:"name"`
+	Email     null.String  `json:"email"`
+	Age       *int         `json:"age"`
+	Score     float64      `json:"score"`
+	Active    bool         `json:"active"`
+	DeletedAt *time.Time   `json:"deleted_at"`
+	Address   benchAddress `json:"address"`
+	Notes     string       `json:"notes"`
+	Tags      []string     `json:"tags"`
+}
+
+func benchPartial() map[string]interface{} {
+	return map[string]interface{}{
+		"id":         "2",
+		"name":       "John",
+		"email":      "john@example.com",
+		"age":        21.0,
+		"score":      1.5,
+		"active":     true,
+		"deleted_at": nil,
+		"address":    map[string]interface{}{"city": "Paris"},
+	}
+}
+
+// benchFlatPartial only has values that both PartialUpdate and baselinePartialUpdate can
+// assign or skip, so that they do the same work
+func benchFlatPartial() map[string]interface{} {
+	return map[string]interface{}{
+		"id":     "2",
+		"name":   "John",
+		"email":  "john@example.com",
+		"score":  1.5,
+		"active": true,
+		"notes":  "none",
+		"tags":   []string{"a", "b"},
+	}
+}
+
+// clearPlans drops all the compiled plans
+func clearPlans() {
+	plans.Range(func(key, _ interface{}) bool {
+		plans.Delete(key)
+		return true
+	})
+}
+
+func TestPlanFor(t *testing.T) {
+	clearPlans()
+	typ := reflect.TypeOf(benchUser{})
+
+	var wg sync.WaitGroup
+	got := make([]*structPlan, 8)
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = planFor(typ, "json")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, p := range got {
+		if p != got[0] {
+			t.Fatalf("planFor() returned different plans for the same type")
+		}
+	}
+	if p := planFor(typ, "db"); p == got[0] {
+		t.Errorf("planFor() returned the same plan for another tag name")
+	}
+	if skip := got[0].skipped(SkipConditions); !skip[0] || skip[1] {
+		t.Errorf("skipped() = %v, want the readonly ID field skipped", skip)
+	}
+}
+
+func TestPartialUpdateSkipConditionsModified(t *testing.T) {
+	type item struct {
+		A string `json:"a"`
+		B string `json:"b"`
+	}
+	skipNamed := func(name string) func(reflect.StructField) bool {
+		return func(field reflect.StructField) bool { return field.Name == name }
+	}
+	skips := []func(reflect.StructField) bool{skipNamed("A")}
+	partial := map[string]interface{}{"a": "a", "b": "b"}
+
+	if got, _ := PartialUpdate(&item{}, partial, "json", skips, Updaters); !reflect.DeepEqual(got, []string{"B"}) {
+		t.Fatalf("PartialUpdate() = %v, want [B]", got)
+	}
+	// the slice is modified in place, the new condition applies to the next updates
+	skips[0] = skipNamed("B")
+	dest := &item{}
+	if got, _ := PartialUpdate(dest, partial, "json", skips, Updaters); !reflect.DeepEqual(got, []string{"A"}) || dest.B != "" {
+		t.Errorf("PartialUpdate() = %v, dest = %+v, want only A updated", got, *dest)
+	}
+	if got, _ := Apply(&item{}, partial, WithSkipConditions(skips...)); !reflect.DeepEqual(got, []string{"A"}) {
+		t.Errorf("Apply() = %v, want [A]", got)
+	}
+}
+
+func TestPartialUpdateUpdatersOrder(t *testing.T) {
+	type item struct {
+		At null.Time `json:"at"`
+	}
+	// fallback accepts any string NullTimeUpdater rejects
+	fallbackUsed := 0
+	fallback := func(fieldValue reflect.Value, v reflect.Value) bool {
+		if _, ok := fieldValue.Interface().(null.Time); !ok || v.Kind() != reflect.String {
+			return false
+		}
+		fallbackUsed++
+		fieldValue.Set(reflect.ValueOf(null.Time{}))
+		return true
+	}
+	updaters := append(append([]func(reflect.Value, reflect.Value) bool(nil), Updaters...), fallback)
+
+	dest := &item{}
+	for _, at := range []string{"garbage", "2020-01-02T03:04:05Z"} {
+		if _, err := PartialUpdate(dest, map[string]interface{}{"at": at}, "json", SkipConditions, updaters); err != nil {
+			t.Fatalf("PartialUpdate(%q) error = %v", at, err)
+		}
+	}
+	if fallbackUsed != 1 || !dest.At.Valid {
+		t.Errorf("PartialUpdate() used the fallback %d times, dest = %+v, want NullTimeUpdater first", fallbackUsed, *dest)
+	}
+}
+
+func TestPartialUpdateConcurrent(t *testing.T) {
+	clearPlans()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				dest := &benchUser{}
+				got, err := PartialUpdate(dest, benchPartial(), "json", SkipConditions, Updaters)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if len(got) != 7 || dest.Name != "John" || dest.Address.City != "Paris" {
+					t.Errorf("PartialUpdate() = %v, dest = %+v", got, dest)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkPartialUpdate(b *testing.B) {
+	partial := benchFlatPartial()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := PartialUpdate(&benchUser{}, partial, "json", SkipConditions, Updaters); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPartialUpdateBaseline runs the PartialUpdate implementation that walked the
+// struct type on every call, before plans were compiled and cached
+func BenchmarkPartialUpdateBaseline(b *testing.B) {
+	partial := benchFlatPartial()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := baselinePartialUpdate(&benchUser{}, partial, "json", SkipConditions, Updaters); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// baselinePartialUpdate is the original PartialUpdate, without the logging of the values
+// that could not be assigned
+func baselinePartialUpdate(dest interface{}, partial map[string]interface{}, tagName string, skipConditions []func(reflect.StructField) bool, updaters []func(reflect.Value, reflect.Value) bool) ([]string, error) {
+	valueOfDest := reflect.ValueOf(dest)
+	// Must be a pointer to a struct so that it can be updated
+	if valueOfDest.Kind() != reflect.Ptr {
+		return nil, errDestinationMustBePointerType
+	}
+	valueOfDest = valueOfDest.Elem()
+
+	typeOfDest := valueOfDest.Type()
+	// Must be a pointer to a struct so that it can be updated
+	if typeOfDest.Kind() != reflect.Struct {
+		return nil, errDestinationMustBeStructType
+	}
+
+	// fieldsUpdated is to keep track all the field names that were successfuly updated
+	fieldsUpdated := make([]string, 0)
+
+	for i := 0; i < typeOfDest.NumField(); i++ {
+		field := typeOfDest.Field(i)
+
+		// skip this field if it cant be set
+		if !valueOfDest.Field(i).CanSet() {
+			continue
+		}
+
+		skip := false
+		// go through all extended skip conditions
+		for _, skipCondition := range skipConditions {
+			skip = skipCondition(field)
+			if skip {
+				// break on the first skip condition found
+				break
+			}
+		}
+		if skip {
+			continue
+		}
+
+		// get the partial value based on the tagName
+		if val, ok := partial[field.Tag.Get(tagName)]; ok {
+			v := reflect.ValueOf(val)
+			updateSuccess := false
+
+			// easily assign the value if both end's kinds are the same
+			if valueOfDest.Field(i).Kind() == v.Kind() {
+				valueOfDest.Field(i).Set(v)
+				updateSuccess = true
+			} else {
+				// go through all extended process types
+				for _, updater := range updaters {
+					updateSuccess = updater(valueOfDest.Field(i), v)
+					if updateSuccess {
+						// the first updateSuccess found, break the loop
+						break
+					}
+				}
+			}
+
+			if updateSuccess {
+				fieldsUpdated = append(fieldsUpdated, field.Name)
+			}
+		}
+	}
+
+	return fieldsUpdated, nil
+}
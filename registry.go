@@ -0,0 +1,207 @@
+package gopartial
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// errNotHandled is returned by a converter that can't convert a value, so that the next one is tried
+var errNotHandled = errors.New("Value not handled")
+
+// converter assigns v to fieldValue. It returns errNotHandled when it can't convert v,
+// and any other error when v is rejected.
+type converter func(fieldValue reflect.Value, v reflect.Value) error
+
+// updaterConverter returns the converter of updater
+func updaterConverter(updater func(reflect.Value, reflect.Value) bool) converter {
+	return func(fieldValue reflect.Value, v reflect.Value) error {
+		if updater(fieldValue, v) {
+			return nil
+		}
+		return errNotHandled
+	}
+}
+
+// registryKey identifies the converters of a destination type or kind for a value kind
+type registryKey struct {
+	// fieldType is the destination type, nil for the converters of a whole kind
+	fieldType reflect.Type
+	fieldKind reflect.Kind
+	// valueKind is the kind of the values converted (reflect.Invalid for null),
+	// ignored when anyValue is set
+	valueKind reflect.Kind
+	anyValue  bool
+}
+
+// Registry holds updaters by destination type, so that the updater of a field is looked up
+// from its type instead of trying the updaters one after the other. It is safe for concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	converters map[registryKey]converter
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{converters: make(map[registryKey]converter)}
+}
+
+// Register registers updater for the fields of type fieldType, replacing the one already registered
+func (r *Registry) Register(fieldType reflect.Type, updater func(reflect.Value, reflect.Value) bool) {
+	r.register(registryKey{fieldType: fieldType, anyValue: true}, updaterConverter(updater))
+}
+
+// RegisterFor registers updater for the fields of type fieldType and the values of kind valueKind
+// (reflect.Invalid for null). It is tried before the updater registered for every kind of value.
+func (r *Registry) RegisterFor(fieldType reflect.Type, valueKind reflect.Kind, updater func(reflect.Value, reflect.Value) bool) {
+	r.register(registryKey{fieldType: fieldType, valueKind: valueKind}, updaterConverter(updater))
+}
+
+// RegisterKind registers updater for the fields of kind fieldKind, e.g. reflect.Int for int and
+// all the named types based on it. It is tried after the updaters registered for the field type.
+func (r *Registry) RegisterKind(fieldKind reflect.Kind, updater func(reflect.Value, reflect.Value) bool) {
+	r.register(registryKey{fieldKind: fieldKind, anyValue: true}, updaterConverter(updater))
+}
+
+// Clone returns a copy of r, which can be changed without changing r
+func (r *Registry) Clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	clone := NewRegistry()
+	for key, c := range r.converters {
+		clone.converters[key] = c
+	}
+	return clone
+}
+
+func (r *Registry) register(key registryKey, c converter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.converters[key] = c
+}
+
+// convert assigns v to fieldValue with the converters registered for its type, then for its kind,
+// the ones for the kind of v first
+func (r *Registry) convert(fieldValue reflect.Value, v reflect.Value) error {
+	fieldType := fieldValue.Type()
+	valueKind := reflect.Invalid
+	if v.IsValid() {
+		valueKind = v.Kind()
+	}
+	keys := [...]registryKey{
+		{fieldType: fieldType, valueKind: valueKind},
+		{fieldType: fieldType, anyValue: true},
+		{fieldKind: fieldType.Kind(), valueKind: valueKind},
+		{fieldKind: fieldType.Kind(), anyValue: true},
+	}
+
+	var converters [len(keys)]converter
+	r.mu.RLock()
+	for i, key := range keys {
+		converters[i] = r.converters[key]
+	}
+	r.mu.RUnlock()
+
+	for i, c := range converters {
+		// types with their own unmarshaling method are unmarshaled before the converters of their kind
+		if i == 2 && (converters[2] != nil || converters[3] != nil) {
+			if err := unmarshal(fieldValue, v); err != errNotHandled {
+				return err
+			}
+		}
+		if c == nil {
+			continue
+		}
+		if err := c(fieldValue, v); err != errNotHandled {
+			return err
+		}
+	}
+
+	return errNotHandled
+}
+
+// RegisterConverter registers fn in r to convert the partial values of type From, or of a type of the
+// same kind convertible to it, to the fields of type To and *To. A null value sets a *To field to nil,
+// and is passed to fn as the zero From when From is a pointer, interface, map or slice type.
+// The error returned by fn rejects the value, it is the Err of its FieldError.
+func RegisterConverter[From any, To any](r *Registry, fn func(From) (To, error)) {
+	fromType := reflect.TypeOf((*From)(nil)).Elem()
+	toType := reflect.TypeOf((*To)(nil)).Elem()
+	c := newConverter(fn)
+
+	for _, fieldType := range []reflect.Type{toType, reflect.PtrTo(toType)} {
+		if fromType.Kind() == reflect.Interface {
+			r.register(registryKey{fieldType: fieldType, anyValue: true}, c)
+		} else {
+			r.register(registryKey{fieldType: fieldType, valueKind: fromType.Kind()}, c)
+		}
+		// null is handled by c before the updaters registered for every kind of value
+		r.register(registryKey{fieldType: fieldType, valueKind: reflect.Invalid}, c)
+	}
+}
+
+// newConverter returns the converter of fn to the fields of type To and *To,
+// see RegisterConverter
+func newConverter[From any, To any](fn func(From) (To, error)) converter {
+	fromType := reflect.TypeOf((*From)(nil)).Elem()
+	toType := reflect.TypeOf((*To)(nil)).Elem()
+	nullable := false
+	switch fromType.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		nullable = true
+	}
+
+	return func(fieldValue reflect.Value, v reflect.Value) error {
+		isPointer := fieldValue.Type() == reflect.PtrTo(toType)
+		if !isPointer && fieldValue.Type() != toType {
+			return errNotHandled
+		}
+		if !v.IsValid() && isPointer {
+			fieldValue.Set(reflect.Zero(fieldValue.Type()))
+			return nil
+		}
+
+		from := reflect.New(fromType).Elem()
+		if v.IsValid() {
+			if v.Type().AssignableTo(fromType) {
+				from.Set(v)
+			} else if v.Kind() == fromType.Kind() && v.Type().ConvertibleTo(fromType) {
+				from.Set(v.Convert(fromType))
+			} else {
+				return errNotHandled
+			}
+		} else if !nullable {
+			return errNotHandled
+		}
+
+		// the type assertion fails for a nil interface, leaving the zero From
+		f, _ := from.Interface().(From)
+		to, err := fn(f)
+		if err != nil {
+			return err
+		}
+		if isPointer {
+			fieldValue.Set(reflect.ValueOf(&to))
+		} else {
+			fieldValue.Set(reflect.ValueOf(&to).Elem())
+		}
+		return nil
+	}
+}
+
+// convert assigns v to fieldValue with the registry, or else with the first of the updaters that succeeds
+func (p *patch) convert(fieldValue reflect.Value, v reflect.Value) error {
+	if p.registry != nil {
+		return p.registry.convert(fieldValue, v)
+	}
+	if p.update(fieldValue, v) {
+		return nil
+	}
+	// numbers rejected by all the updaters are converted to a scratch value to know why,
+	// so that only the updaters ever assign the field
+	if err := convertNumber(reflect.New(fieldValue.Type()).Elem(), v); err != nil && err != errNotHandled {
+		return err
+	}
+	return errNotHandled
+}
@@ -0,0 +1,190 @@
+package gopartial
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+type registryStatus struct {
+	Code string
+}
+
+type registryLevel int
+
+// registryStatusUpdater update registryStatus from an upper cased string
+func registryStatusUpdater(fieldValue reflect.Value, v reflect.Value) bool {
+	if v.Kind() == reflect.String {
+		fieldValue.Set(reflect.ValueOf(registryStatus{Code: strings.ToUpper(v.String())}))
+		return true
+	}
+	return false
+}
+
+// registryStatusNullUpdater update registryStatus with "NONE" for a null value
+func registryStatusNullUpdater(fieldValue reflect.Value, v reflect.Value) bool {
+	fieldValue.Set(reflect.ValueOf(registryStatus{Code: "NONE"}))
+	return true
+}
+
+func TestRegistry(t *testing.T) {
+	type user struct {
+		Name     string         `json:"name"`
+		Status   registryStatus `json:"status"`
+		Level    registryLevel  `json:"level"`
+		Email    null.String    `json:"email"`
+		Age      *int           `json:"age"`
+		Score    float32        `json:"score"`
+		Birthday *time.Time     `json:"birthday"`
+		Meta     map[string]int `json:"meta"`
+		Address  *struct{}      `json:"address"`
+	}
+
+	registry := DefaultRegistry.Clone()
+	registry.Register(reflect.TypeOf(registryStatus{}), registryStatusUpdater)
+	registry.RegisterFor(reflect.TypeOf(registryStatus{}), reflect.Invalid, registryStatusNullUpdater)
+
+	dest := &user{Status: registryStatus{Code: "ACTIVE"}, Address: &struct{}{}}
+	partial := map[string]interface{}{
+		"name":     "John",
+		"status":   "inactive",
+		"level":    2.0,
+		"email":    "john@example.com",
+		"age":      21.0,
+		"score":    1,
+		"birthday": "2020-01-02T15:04:05Z",
+		"meta":     map[string]interface{}{"visits": 3.0},
+		"address":  nil,
+	}
+	updated, err := NewPatcher(WithRegistry(registry)).Apply(dest, partial)
+	if err != nil {
+		t.Fatalf("Patcher.Apply() error = %v", err)
+	}
+	if len(updated) != len(partial) {
+		t.Errorf("Patcher.Apply() updated = %v, want all the fields", updated)
+	}
+	if dest.Name != "John" || dest.Status.Code != "INACTIVE" || dest.Level != 2 || dest.Email.String != "john@example.com" ||
+		*dest.Age != 21 || dest.Score != 1 || dest.Birthday.Year() != 2020 || dest.Meta["visits"] != 3 || dest.Address != nil {
+		t.Errorf("Patcher.Apply() dest = %+v", dest)
+	}
+
+	// the updater registered for null values is preferred over the one for every kind of value
+	if _, err := NewPatcher(WithRegistry(registry)).Apply(dest, map[string]interface{}{"status": nil}); err != nil || dest.Status.Code != "NONE" {
+		t.Errorf("Patcher.Apply() status = %v, error = %v, want NONE", dest.Status, err)
+	}
+
+	// a value of a kind that no updater handles is rejected
+	if _, err := NewPatcher(WithRegistry(registry)).Apply(dest, map[string]interface{}{"status": true}); err == nil {
+		t.Errorf("Patcher.Apply() error = nil, want an error")
+	}
+
+	// the clone doesn't change the registry it was cloned from
+	if _, err := NewPatcher().Apply(dest, map[string]interface{}{"status": nil}); err == nil {
+		t.Errorf("Patcher.Apply() error = nil, want an error with DefaultRegistry")
+	}
+
+	// the patcher keeps a copy of the registry, which later registrations don't change
+	before := DefaultRegistry.Clone()
+	patcher := NewPatcher(WithRegistry(before))
+	before.RegisterFor(reflect.TypeOf(registryStatus{}), reflect.Invalid, registryStatusNullUpdater)
+	if _, err := patcher.Apply(dest, map[string]interface{}{"status": nil}); err == nil {
+		t.Errorf("Patcher.Apply() error = nil, want an error with the registry it was created with")
+	}
+
+	// the updaters slice is still accepted
+	dest = &user{}
+	if _, err := NewPatcher(WithUpdaters(Updaters...)).Apply(dest, map[string]interface{}{"email": "jane@example.com", "age": 3}); err != nil {
+		t.Errorf("Patcher.Apply() error = %v", err)
+	}
+	if dest.Email.String != "jane@example.com" || *dest.Age != 3 {
+		t.Errorf("Patcher.Apply() dest = %+v", dest)
+	}
+}
+
+type registryMoney int64
+
+var errRegistryMoney = errors.New("Invalid amount")
+
+func parseRegistryMoney(s string) (registryMoney, error) {
+	var units, cents int64
+	if _, err := fmt.Sscanf(s, "%d.%02d", &units, &cents); err != nil {
+		return 0, errRegistryMoney
+	}
+	return registryMoney(units*100 + cents), nil
+}
+
+func TestRegisterConverter(t *testing.T) {
+	type order struct {
+		Total    registryMoney  `json:"total"`
+		Discount *registryMoney `json:"discount"`
+	}
+	registry := DefaultRegistry.Clone()
+	RegisterConverter(registry, parseRegistryMoney)
+	patcher := NewPatcher(WithRegistry(registry))
+
+	dest := &order{}
+	updated, err := patcher.Apply(dest, map[string]interface{}{
+		"total":    "12.34",
+		"discount": "1.50",
+	})
+	if err != nil {
+		t.Fatalf("Patcher.Apply() error = %v", err)
+	}
+	if want := []string{"Total", "Discount"}; !reflect.DeepEqual(updated, want) {
+		t.Errorf("Patcher.Apply() updated = %v, want %v", updated, want)
+	}
+	if dest.Total != 1234 || dest.Discount == nil || *dest.Discount != 150 {
+		t.Errorf("Patcher.Apply() dest = %+v", dest)
+	}
+
+	// null sets the pointer to nil, and is rejected for the value
+	if _, err := patcher.Apply(dest, map[string]interface{}{"discount": nil}); err != nil {
+		t.Fatalf("Patcher.Apply() error = %v", err)
+	}
+	if _, err := patcher.Apply(dest, map[string]interface{}{"total": nil}); err == nil {
+		t.Errorf("Patcher.Apply() error = nil, want an error")
+	}
+	if dest.Discount != nil || dest.Total != 1234 {
+		t.Errorf("Patcher.Apply() dest = %+v", dest)
+	}
+
+	// the errors of the converter are returned
+	_, err = patcher.Apply(dest, map[string]interface{}{"total": "twelve", "discount": true})
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) || len(patchErr.Fields) != 2 {
+		t.Fatalf("Patcher.Apply() error = %v, want 2 field errors", err)
+	}
+	if !errors.Is(err, errRegistryMoney) || patchErr.Fields[0].Reason != errRegistryMoney.Error() {
+		t.Errorf("Patcher.Apply() error = %v, want %v", err, errRegistryMoney)
+	}
+	if patchErr.Fields[1].Reason != reasonIncompatible || patchErr.Fields[1].Err != nil {
+		t.Errorf("Patcher.Apply() error = %v, want discount incompatible", patchErr.Fields[1])
+	}
+}
+
+func BenchmarkPatcherRegistry(b *testing.B) {
+	patcher := NewPatcher()
+	partial := benchPartial()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := patcher.Apply(&benchUser{}, partial); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPatcherUpdaters(b *testing.B) {
+	patcher := NewPatcher(WithUpdaters(Updaters...))
+	partial := benchPartial()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := patcher.Apply(&benchUser{}, partial); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package gopartial
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// partialTagName is the name of the struct tag holding the gopartial options of a field,
+// e.g. `partial:"conv=cents"`
+const partialTagName = "partial"
+
+// tagOptions is the string following a comma in a struct tag, e.g. "omitempty,string"
+type tagOptions string
+
+// parseTag splits a struct tag into its name and its comma-separated options
+func parseTag(tag string) (string, tagOptions) {
+	if idx := strings.Index(tag, ","); idx != -1 {
+		return tag[:idx], tagOptions(tag[idx+1:])
+	}
+	return tag, tagOptions("")
+}
+
+// Contains reports whether the comma-separated options contain the option name
+func (o tagOptions) Contains(name string) bool {
+	s := string(o)
+	for s != "" {
+		var next string
+		if i := strings.Index(s, ","); i >= 0 {
+			s, next = s[:i], s[i+1:]
+		}
+		if s == name {
+			return true
+		}
+		s = next
+	}
+	return false
+}
+
+// Value returns the value of the option name in the comma-separated options, e.g. "cents" for
+// the option "conv" in "conv=cents", and an empty string when there is no such option
+func (o tagOptions) Value(name string) string {
+	s := string(o)
+	for s != "" {
+		var next string
+		if i := strings.Index(s, ","); i >= 0 {
+			s, next = s[:i], s[i+1:]
+		}
+		if strings.HasPrefix(s, name+"=") {
+			return s[len(name)+1:]
+		}
+		s = next
+	}
+	return ""
+}
+
+// unquote converts val, the string value of a field tagged with the ",string" option,
+// to the number or bool it encodes so it can be assigned like any other number or bool.
+// val is returned as is when it is not a string or can't be parsed.
+func unquote(fieldType reflect.Type, val interface{}) interface{} {
+	s, ok := val.(string)
+	if !ok {
+		return val
+	}
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return u
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+
+	return val
+}
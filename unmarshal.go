@@ -0,0 +1,76 @@
+package gopartial
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// isUnmarshaler returns whether the pointer type t implements one of the interfaces used by unmarshal
+func isUnmarshaler(t reflect.Type) bool {
+	return t.Implements(textUnmarshalerType) || t.Implements(jsonUnmarshalerType) || t.Implements(scannerType)
+}
+
+// unmarshal assigns v to fieldValue through the encoding.TextUnmarshaler, json.Unmarshaler or
+// sql.Scanner implementation of its type. The field only accepts null when its type is a sql.Scanner,
+// which is scanned from nil. It is only set when v could be unmarshaled, so that it is left untouched
+// otherwise. Pointers to these types are allocated by assignPointer.
+func unmarshal(fieldValue reflect.Value, v reflect.Value) error {
+	fieldType := fieldValue.Type()
+	if !isUnmarshaler(reflect.PtrTo(fieldType)) {
+		return errNotHandled
+	}
+	if !v.IsValid() && !reflect.PtrTo(fieldType).Implements(scannerType) {
+		return errNotHandled
+	}
+
+	target := reflect.New(fieldType)
+	if err := unmarshalInto(target, v); err != nil {
+		return err
+	}
+	fieldValue.Set(target.Elem())
+	return nil
+}
+
+// unmarshalInto unmarshals v into the value target points to. Null is scanned when possible,
+// strings are unmarshaled as text when possible, other values as JSON, and values that can't
+// be are scanned.
+func unmarshalInto(target reflect.Value, v reflect.Value) error {
+	if scanner, ok := target.Interface().(sql.Scanner); ok && !v.IsValid() {
+		return scanner.Scan(nil)
+	}
+
+	textUnmarshaler, isText := target.Interface().(encoding.TextUnmarshaler)
+	if isText && v.IsValid() && v.Kind() == reflect.String {
+		return textUnmarshaler.UnmarshalText([]byte(v.String()))
+	}
+
+	if jsonUnmarshaler, ok := target.Interface().(json.Unmarshaler); ok {
+		var val interface{}
+		if v.IsValid() {
+			val = v.Interface()
+		}
+		data, err := json.Marshal(val)
+		if err != nil {
+			return errNotHandled
+		}
+		return jsonUnmarshaler.UnmarshalJSON(data)
+	}
+
+	if scanner, ok := target.Interface().(sql.Scanner); ok {
+		var val interface{}
+		if v.IsValid() {
+			val = v.Interface()
+		}
+		return scanner.Scan(val)
+	}
+
+	return errNotHandled
+}
@@ -0,0 +1,175 @@
+package gopartial
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/guregu/null"
+)
+
+// textID implements encoding.TextUnmarshaler, like uuid types do
+type textID [4]byte
+
+var errTextID = errors.New("Invalid ID")
+
+func (id *textID) UnmarshalText(text []byte) error {
+	if len(text) != len(id) {
+		return errTextID
+	}
+	copy(id[:], text)
+	return nil
+}
+
+// jsonPoint implements json.Unmarshaler from a [x, y] array
+type jsonPoint struct {
+	X, Y int
+}
+
+func (p *jsonPoint) UnmarshalJSON(data []byte) error {
+	var xy [2]int
+	if err := json.Unmarshal(data, &xy); err != nil {
+		return err
+	}
+	p.X, p.Y = xy[0], xy[1]
+	return nil
+}
+
+// scanCode implements sql.Scanner, like decimal types do
+type scanCode struct {
+	Code  string
+	Valid bool
+}
+
+func (c *scanCode) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = scanCode{}
+	case float64:
+		*c = scanCode{Code: fmt.Sprintf("C%v", v), Valid: true}
+	default:
+		return fmt.Errorf("Cannot scan %T", value)
+	}
+	return nil
+}
+
+func TestPartialUpdateUnmarshalers(t *testing.T) {
+	type item struct {
+		ID       textID       `json:"id"`
+		IDPtr    *textID      `json:"id_ptr"`
+		Position jsonPoint    `json:"position"`
+		Code     scanCode     `json:"code"`
+		Name     *null.String `json:"name"`
+	}
+
+	dest := &item{Code: scanCode{Code: "C1", Valid: true}}
+	updated, err := PartialUpdate(dest, map[string]interface{}{
+		"id":       "abcd",
+		"id_ptr":   "efgh",
+		"position": []interface{}{1, 2},
+		"code":     nil,
+		"name":     "John",
+	}, "json", SkipConditions, Updaters)
+	if err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if want := []string{"ID", "IDPtr", "Position", "Code", "Name"}; !reflect.DeepEqual(updated, want) {
+		t.Errorf("PartialUpdate() updated = %v, want %v", updated, want)
+	}
+	if string(dest.ID[:]) != "abcd" || dest.IDPtr == nil || string(dest.IDPtr[:]) != "efgh" || dest.Position != (jsonPoint{1, 2}) ||
+		dest.Code.Valid || dest.Name == nil || dest.Name.String != "John" {
+		t.Errorf("PartialUpdate() dest = %+v", dest)
+	}
+
+	// null sets pointers to nil, values are scanned
+	updated, err = PartialUpdate(dest, map[string]interface{}{
+		"id_ptr": nil,
+		"code":   2.0,
+		"name":   nil,
+	}, "json", SkipConditions, Updaters)
+	if err != nil {
+		t.Fatalf("PartialUpdate() error = %v", err)
+	}
+	if len(updated) != 3 || dest.IDPtr != nil || dest.Code != (scanCode{Code: "C2", Valid: true}) || dest.Name != nil {
+		t.Errorf("PartialUpdate() dest = %+v", dest)
+	}
+
+	// the errors of the unmarshalers are returned, and the fields left untouched
+	_, err = PartialUpdate(dest, map[string]interface{}{
+		"id":       "toolong",
+		"position": "here",
+		"code":     true,
+	}, "json", SkipConditions, Updaters)
+	var patchErr *PatchError
+	if !errors.As(err, &patchErr) || len(patchErr.Fields) != 3 {
+		t.Fatalf("PartialUpdate() error = %v, want 3 field errors", err)
+	}
+	if !errors.Is(err, errTextID) {
+		t.Errorf("PartialUpdate() error = %v, want %v", err, errTextID)
+	}
+	if !strings.Contains(patchErr.Fields[2].Reason, "Cannot scan bool") {
+		t.Errorf("PartialUpdate() error = %v, want the scan error", patchErr.Fields[2])
+	}
+	if string(dest.ID[:]) != "abcd" || dest.Position != (jsonPoint{1, 2}) || dest.Code.Code != "C2" {
+		t.Errorf("PartialUpdate() dest = %+v, want it untouched", dest)
+	}
+
+	// null is rejected for values that can't be scanned
+	if _, err := PartialUpdate(dest, map[string]interface{}{"position": nil}, "json", SkipConditions, Updaters); err == nil {
+		t.Errorf("PartialUpdate() error = nil, want an error")
+	}
+}
+
+// testLevel implements json.Unmarshaler for a number, which it scales
+type testLevel int
+
+func (l *testLevel) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*l = testLevel(n * 10)
+	return nil
+}
+
+func TestPartialUpdateNumericUnmarshaler(t *testing.T) {
+	type item struct {
+		Level    testLevel  `json:"level"`
+		LevelPtr *testLevel `json:"level_ptr"`
+		Count    null.Int   `json:"count"`
+	}
+	partial := map[string]interface{}{"level": 3.0, "level_ptr": 4, "count": 5.0}
+
+	// the unmarshaling method is used before the converters of the int kind, like encoding/json does
+	var want item
+	data, _ := json.Marshal(partial)
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	for _, apply := range []func(*item) error{
+		func(dest *item) error {
+			_, err := Apply(dest, partial)
+			return err
+		},
+		func(dest *item) error {
+			_, err := PartialUpdate(dest, partial, "json", SkipConditions, Updaters)
+			return err
+		},
+	} {
+		dest := &item{}
+		if err := apply(dest); err != nil {
+			t.Fatalf("error = %v", err)
+		}
+		if !reflect.DeepEqual(*dest, want) || dest.Level != 30 {
+			t.Errorf("dest = %+v, want %+v", *dest, want)
+		}
+	}
+
+	// the errors of the method reject the value
+	if _, err := Apply(&item{}, map[string]interface{}{"level": 1.5}); err == nil {
+		t.Errorf("Apply() error = nil, want an error")
+	}
+}
@@ -31,62 +31,58 @@ func NullStringUpdater(fieldValue reflect.Value, v reflect.Value) bool {
 
 // NullFloatUpdater update null.Float64
 func NullFloatUpdater(fieldValue reflect.Value, v reflect.Value) bool {
+	return convertNullFloat(fieldValue, v) == nil
+}
+
+// convertNullFloat converts null or any int/float v to the null.Float field fieldValue
+func convertNullFloat(fieldValue reflect.Value, v reflect.Value) error {
 	switch fieldValue.Interface().(type) {
 	case null.Float:
 		// if its null value
 		if !v.IsValid() {
 			newValue := reflect.ValueOf(null.Float{NullFloat64: sql.NullFloat64{Valid: false}})
 			fieldValue.Set(newValue)
-			return true
+			return nil
 		}
-		// only set if underlying type is any int/float
-		if v.Kind() == reflect.Int ||
-			v.Kind() == reflect.Int8 ||
-			v.Kind() == reflect.Int16 ||
-			v.Kind() == reflect.Int32 ||
-			v.Kind() == reflect.Int64 {
-			newValue := reflect.ValueOf(null.Float{NullFloat64: sql.NullFloat64{Valid: true, Float64: float64(v.Int())}})
-			fieldValue.Set(newValue)
-			return true
-		} else if v.Kind() == reflect.Float32 ||
-			v.Kind() == reflect.Float64 {
-			newValue := reflect.ValueOf(null.Float{NullFloat64: sql.NullFloat64{Valid: true, Float64: v.Float()}})
-			fieldValue.Set(newValue)
-			return true
+		// only set if underlying type is any int/uint/float
+		f, err := floatValue(v, 64)
+		if err != nil {
+			return err
 		}
+		newValue := reflect.ValueOf(null.Float{NullFloat64: sql.NullFloat64{Valid: true, Float64: f}})
+		fieldValue.Set(newValue)
+		return nil
 	}
 
-	return false
+	return errNotHandled
 }
 
 // NullIntUpdater update null.Int
 func NullIntUpdater(fieldValue reflect.Value, v reflect.Value) bool {
+	return convertNullInt(fieldValue, v) == nil
+}
+
+// convertNullInt converts null or any int/uint/integral float v to the null.Int field fieldValue
+func convertNullInt(fieldValue reflect.Value, v reflect.Value) error {
 	switch fieldValue.Interface().(type) {
 	case null.Int:
 		// if its null value
 		if !v.IsValid() {
 			newValue := reflect.ValueOf(null.Int{NullInt64: sql.NullInt64{Valid: false}})
 			fieldValue.Set(newValue)
-			return true
+			return nil
 		}
-		// only set if underlying type is any int/float
-		if v.Kind() == reflect.Int ||
-			v.Kind() == reflect.Int8 ||
-			v.Kind() == reflect.Int16 ||
-			v.Kind() == reflect.Int32 ||
-			v.Kind() == reflect.Int64 {
-			newValue := reflect.ValueOf(null.Int{NullInt64: sql.NullInt64{Valid: true, Int64: v.Int()}})
-			fieldValue.Set(newValue)
-			return true
-		} else if v.Kind() == reflect.Float32 ||
-			v.Kind() == reflect.Float64 {
-			newValue := reflect.ValueOf(null.Int{NullInt64: sql.NullInt64{Valid: true, Int64: int64(v.Float())}})
-			fieldValue.Set(newValue)
-			return true
+		// only set if underlying type is any int/uint/float
+		i, err := intValue(v, 64)
+		if err != nil {
+			return err
 		}
+		newValue := reflect.ValueOf(null.Int{NullInt64: sql.NullInt64{Valid: true, Int64: i}})
+		fieldValue.Set(newValue)
+		return nil
 	}
 
-	return false
+	return errNotHandled
 }
 
 // NullBoolUpdater update null.Bool
@@ -116,7 +112,7 @@ func NullTimeUpdater(fieldValue reflect.Value, v reflect.Value) bool {
 	case null.Time:
 		// if its null value
 		if !v.IsValid() {
-			newValue := reflect.ValueOf(null.Time{sql.NullTime{Valid: false}})
+			newValue := reflect.ValueOf(null.Time{NullTime: sql.NullTime{Valid: false}})
 			fieldValue.Set(newValue)
 			return true
 		}
@@ -134,270 +130,305 @@ func NullTimeUpdater(fieldValue reflect.Value, v reflect.Value) bool {
 	return false
 }
 
-// MapStringInterfaceUpdater update map[string]interface{}
+// SQLNullUpdater update the database/sql null types (sql.NullString, sql.NullInt64, sql.NullInt32,
+// sql.NullInt16, sql.NullByte, sql.NullFloat64, sql.NullBool, sql.NullTime and sql.Null[T]). Null
+// sets them to invalid, and the other values are converted to their value type through the updaters
+// in use (Updaters when called on its own)
+func SQLNullUpdater(fieldValue reflect.Value, v reflect.Value) bool {
+	return isSQLNull(fieldValue.Type()) && assignSQLNull(fieldValue, v, assignElement) == nil
+}
+
+// isSQLNull returns whether t is one of the database/sql null types, a struct of the value and
+// its Valid flag
+func isSQLNull(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == "database/sql" && t.NumField() == 2 &&
+		t.Field(1).Name == "Valid" && t.Field(1).Type.Kind() == reflect.Bool
+}
+
+// assignSQLNull sets the database/sql null type field fieldValue to invalid for null, or else to
+// the valid value v is assigned to with assign, starting from the current value
+func assignSQLNull(fieldValue reflect.Value, v reflect.Value, assign func(reflect.Value, reflect.Value) error) error {
+	if !v.IsValid() {
+		fieldValue.Set(reflect.Zero(fieldValue.Type()))
+		return nil
+	}
+
+	elem := reflect.New(fieldValue.Type()).Elem()
+	elem.Set(fieldValue)
+	if err := assign(elem.Field(0), v); err != nil {
+		return err
+	}
+	elem.Field(1).SetBool(true)
+	fieldValue.Set(elem)
+	return nil
+}
+
+// MapStringInterfaceUpdater update map[string]interface{} (or any map with string keys) by merging
+// a map[string]interface{} into it. Entries with null value are deleted, nested objects are merged
+// and the other values are converted to the map element type through the updaters in use (Updaters
+// when called on its own)
 func MapStringInterfaceUpdater(fieldValue reflect.Value, v reflect.Value) bool {
-	// if fieldValue.Kind() == reflect.Struct {
-	// 	// @TODO: right now, do nothing
-	// 	return false
-	// }
-	return false
+	return mergeMap(fieldValue, v, updateElement)
 }
 
-// BoolUpdater update bool (pointer or value)
-func BoolUpdater(fieldValue reflect.Value, v reflect.Value) bool {
-	if fieldValue.Kind() == reflect.Bool {
-		if v.Kind() == reflect.Bool {
-			fieldValue.Set(v)
+// updateElement updates elem, a map element or the value of a database/sql null type, with v
+// like Updaters do. Pointer elements are allocated and their value updated the same way
+func updateElement(elem reflect.Value, v reflect.Value) bool {
+	for _, updater := range elementUpdaters {
+		if updater(elem, v) {
 			return true
 		}
-	} else if fieldValue.Kind() == reflect.Ptr {
-		// only process if field is pointer to any bool
-		if fieldValue.Type().String() == "*bool" {
-			if !v.IsValid() {
-				var newBoolValue *bool
-				newValue := reflect.ValueOf(newBoolValue)
-				fieldValue.Set(newValue)
-				return true
-			} else if v.Kind() == reflect.Bool {
-				newBoolValue := v.Bool()
-				newValue := reflect.ValueOf(&newBoolValue)
-				fieldValue.Set(newValue)
+	}
+	if isSQLNull(elem.Type()) {
+		return assignSQLNull(elem, v, assignElement) == nil
+	}
+	if elem.Kind() == reflect.Map {
+		return mergeMap(elem, v, updateElement)
+	}
+	return elem.Kind() == reflect.Ptr && assignPointer(elem, v, assignElement) == nil
+}
+
+// assignElement assigns v to elem as is when its type is assignable, or else with updateElement
+func assignElement(elem reflect.Value, v reflect.Value) error {
+	if v.IsValid() && v.Type().AssignableTo(elem.Type()) && isFinite(v) {
+		elem.Set(v)
+		return nil
+	}
+	if !updateElement(elem, v) {
+		return errNotHandled
+	}
+	return nil
+}
+
+// mergeMap merges v into the map with string keys fieldValue, converting the elements with convert
+func mergeMap(fieldValue reflect.Value, v reflect.Value, convert func(reflect.Value, reflect.Value) bool) bool {
+	// only process if field is a map with string keys
+	if fieldValue.Kind() != reflect.Map || fieldValue.Type().Key().Kind() != reflect.String {
+		return false
+	}
+	// if its null value
+	if !v.IsValid() {
+		fieldValue.Set(reflect.Zero(fieldValue.Type()))
+		return true
+	}
+	partial, ok := v.Interface().(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	// convert all entries first so that the map is left untouched if any of them fails
+	entries := make(map[string]reflect.Value, len(partial))
+	for key, val := range partial {
+		// a null value deletes the entry
+		if val == nil {
+			entries[key] = reflect.Value{}
+			continue
+		}
+
+		elem := reflect.New(fieldValue.Type().Elem()).Elem()
+		if !fieldValue.IsNil() {
+			if existing := fieldValue.MapIndex(reflect.ValueOf(key).Convert(fieldValue.Type().Key())); existing.IsValid() {
+				elem.Set(existing)
+			}
+		}
+		if !updateMapElement(elem, reflect.ValueOf(val), convert) {
+			return false
+		}
+		entries[key] = elem
+	}
+
+	if fieldValue.IsNil() {
+		fieldValue.Set(reflect.MakeMap(fieldValue.Type()))
+	}
+	for key, elem := range entries {
+		fieldValue.SetMapIndex(reflect.ValueOf(key).Convert(fieldValue.Type().Key()), elem)
+	}
+	return true
+}
+
+// updateMapElement updates elem, a settable copy of a map element, with v
+func updateMapElement(elem reflect.Value, v reflect.Value, convert func(reflect.Value, reflect.Value) bool) bool {
+	if _, isMap := v.Interface().(map[string]interface{}); isMap {
+		// merge into a copy of the existing nested map, so that the original one is never modified
+		nested := elem
+		if nested.Kind() == reflect.Interface {
+			if nested.IsNil() {
+				nested = reflect.Zero(v.Type())
+			} else {
+				nested = nested.Elem()
+			}
+		}
+		if nested.Kind() == reflect.Map && nested.Type().Key().Kind() == reflect.String {
+			merged := reflect.MakeMap(nested.Type())
+			for _, key := range nested.MapKeys() {
+				merged.SetMapIndex(key, nested.MapIndex(key))
+			}
+			copied := reflect.New(nested.Type()).Elem()
+			copied.Set(merged)
+			if mergeMap(copied, v, convert) {
+				elem.Set(copied)
 				return true
 			}
+			return false
 		}
 	}
 
+	if v.Type().AssignableTo(elem.Type()) {
+		elem.Set(v)
+		return true
+	}
+	return convert(elem, v)
+}
+
+// BoolUpdater update bool
+func BoolUpdater(fieldValue reflect.Value, v reflect.Value) bool {
+	if fieldValue.Kind() != reflect.Bool {
+		return false
+	}
+	// named types with their own unmarshaling method are unmarshaled like encoding/json does
+	if err := unmarshal(fieldValue, v); err != errNotHandled {
+		return err == nil
+	}
+	if v.Kind() == reflect.Bool {
+		fieldValue.SetBool(v.Bool())
+		return true
+	}
+
 	return false
 }
 
-// IntUpdater update int (any int type Int8, Int16, Int32, Int64 and whether its a pointer or a value)
+// IntUpdater update int (any int type Int8, Int16, Int32, Int64)
 func IntUpdater(fieldValue reflect.Value, v reflect.Value) bool {
-	if fieldValue.Kind() == reflect.Int ||
-		fieldValue.Kind() == reflect.Int8 ||
-		fieldValue.Kind() == reflect.Int16 ||
-		fieldValue.Kind() == reflect.Int32 ||
-		fieldValue.Kind() == reflect.Int64 {
-
-		if v.Kind() == reflect.Int ||
-			v.Kind() == reflect.Int8 ||
-			v.Kind() == reflect.Int16 ||
-			v.Kind() == reflect.Int32 ||
-			v.Kind() == reflect.Int64 {
-			fieldValue.SetInt(v.Int())
-			return true
-		} else if v.Kind() == reflect.Float32 ||
-			v.Kind() == reflect.Float64 {
-			fieldValue.SetInt(int64(v.Float()))
-			return true
+	return convertInt(fieldValue, v) == nil
+}
+
+// convertInt converts any int/uint/integral float v that fits in the int field fieldValue
+func convertInt(fieldValue reflect.Value, v reflect.Value) error {
+	switch fieldValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// named types with their own unmarshaling method are unmarshaled like encoding/json does
+		if err := unmarshal(fieldValue, v); err != errNotHandled {
+			return err
 		}
-	} else if fieldValue.Kind() == reflect.Ptr {
-		// only process if field is pointer to any int
-		if fieldValue.Type().String() == "*int" ||
-			fieldValue.Type().String() == "*int8" ||
-			fieldValue.Type().String() == "*int16" ||
-			fieldValue.Type().String() == "*int32" ||
-			fieldValue.Type().String() == "*int64" {
-			if !v.IsValid() {
-				if fieldValue.Type().String() == "*int" {
-					var newNullInt *int
-					newValue := reflect.ValueOf(newNullInt)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int8" {
-					var newNullInt8 *int8
-					newValue := reflect.ValueOf(newNullInt8)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int16" {
-					var newNullInt16 *int16
-					newValue := reflect.ValueOf(newNullInt16)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int32" {
-					var newNullInt32 *int32
-					newValue := reflect.ValueOf(newNullInt32)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int64" {
-					var newNullInt64 *int64
-					newValue := reflect.ValueOf(newNullInt64)
-					fieldValue.Set(newValue)
-					return true
-				}
-			} else if v.Kind() == reflect.Int ||
-				v.Kind() == reflect.Int8 ||
-				v.Kind() == reflect.Int16 ||
-				v.Kind() == reflect.Int32 ||
-				v.Kind() == reflect.Int64 {
-				if fieldValue.Type().String() == "*int" {
-					newIntValue := int(v.Int())
-					newValue := reflect.ValueOf(&newIntValue)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int8" {
-					newInt8Value := int8(v.Int())
-					newValue := reflect.ValueOf(&newInt8Value)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int16" {
-					newInt16Value := int16(v.Int())
-					newValue := reflect.ValueOf(&newInt16Value)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int32" {
-					newInt32Value := int32(v.Int())
-					newValue := reflect.ValueOf(&newInt32Value)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int64" {
-					newInt64Value := v.Int()
-					newValue := reflect.ValueOf(&newInt64Value)
-					fieldValue.Set(newValue)
-					return true
-				}
-			} else if v.Kind() == reflect.Float32 ||
-				v.Kind() == reflect.Float64 {
-				if fieldValue.Type().String() == "*int" {
-					newIntValue := int(v.Float())
-					newValue := reflect.ValueOf(&newIntValue)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int8" {
-					newInt8Value := int8(v.Float())
-					newValue := reflect.ValueOf(&newInt8Value)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int16" {
-					newInt16Value := int16(v.Float())
-					newValue := reflect.ValueOf(&newInt16Value)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int32" {
-					newInt32Value := int32(v.Float())
-					newValue := reflect.ValueOf(&newInt32Value)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*int64" {
-					newInt64Value := int64(v.Float())
-					newValue := reflect.ValueOf(&newInt64Value)
-					fieldValue.Set(newValue)
-					return true
-				}
-			}
+		i, err := intValue(v, fieldValue.Type().Bits())
+		if err != nil {
+			return err
 		}
+		fieldValue.SetInt(i)
+		return nil
 	}
 
-	return false
+	return errNotHandled
 }
 
-// FloatUpdater update int (any float type Float8, Float16, Float32, Float64 and whether its a pointer or a value)
+// FloatUpdater update int (any float type Float8, Float16, Float32, Float64)
 func FloatUpdater(fieldValue reflect.Value, v reflect.Value) bool {
-	if fieldValue.Kind() == reflect.Float32 ||
-		fieldValue.Kind() == reflect.Float64 {
-
-		if v.Kind() == reflect.Int ||
-			v.Kind() == reflect.Int8 ||
-			v.Kind() == reflect.Int16 ||
-			v.Kind() == reflect.Int32 ||
-			v.Kind() == reflect.Int64 {
-			fieldValue.SetFloat(float64(v.Int()))
-			return true
-		} else if v.Kind() == reflect.Float32 ||
-			v.Kind() == reflect.Float64 {
-			fieldValue.SetFloat(v.Float())
-			return true
+	return convertFloat(fieldValue, v) == nil
+}
+
+// convertFloat converts any finite int/uint/float v that fits in the float field fieldValue
+func convertFloat(fieldValue reflect.Value, v reflect.Value) error {
+	switch fieldValue.Kind() {
+	case reflect.Float32, reflect.Float64:
+		// named types with their own unmarshaling method are unmarshaled like encoding/json does
+		if err := unmarshal(fieldValue, v); err != errNotHandled {
+			return err
 		}
-	} else if fieldValue.Kind() == reflect.Ptr {
-		// only process if field is pointer to any float
-		if fieldValue.Type().String() == "*float32" ||
-			fieldValue.Type().String() == "*float64" {
-			if !v.IsValid() {
-				if fieldValue.Type().String() == "*float32" {
-					var newFloat32Value *float32
-					newValue := reflect.ValueOf(newFloat32Value)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*float64" {
-					var newFloat64Value *float64
-					newValue := reflect.ValueOf(newFloat64Value)
-					fieldValue.Set(newValue)
-					return true
-				}
-			} else if v.Kind() == reflect.Int ||
-				v.Kind() == reflect.Int8 ||
-				v.Kind() == reflect.Int16 ||
-				v.Kind() == reflect.Int32 ||
-				v.Kind() == reflect.Int64 {
-				if fieldValue.Type().String() == "*float32" {
-					newFloat32Value := float32(v.Int())
-					newValue := reflect.ValueOf(&newFloat32Value)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*float64" {
-					newFloat64Value := float64(v.Int())
-					newValue := reflect.ValueOf(&newFloat64Value)
-					fieldValue.Set(newValue)
-					return true
-				}
-			} else if v.Kind() == reflect.Float32 ||
-				v.Kind() == reflect.Float64 {
-				if fieldValue.Type().String() == "*float32" {
-					newFloat32Value := float32(v.Float())
-					newValue := reflect.ValueOf(&newFloat32Value)
-					fieldValue.Set(newValue)
-					return true
-				} else if fieldValue.Type().String() == "*float64" {
-					newFloat64Value := v.Float()
-					newValue := reflect.ValueOf(&newFloat64Value)
-					fieldValue.Set(newValue)
-					return true
-				}
-			}
+		f, err := floatValue(v, fieldValue.Type().Bits())
+		if err != nil {
+			return err
 		}
+		fieldValue.SetFloat(f)
+		return nil
 	}
 
-	return false
+	return errNotHandled
 }
 
-// TimeUpdater update time (pointer or value)
-func TimeUpdater(fieldValue reflect.Value, v reflect.Value) bool {
-	switch fieldValue.Interface().(type) {
-	case time.Time:
-		if !v.IsValid() {
-			return false
+// UintUpdater update uint (any uint type Uint8, Uint16, Uint32, Uint64, Uintptr)
+func UintUpdater(fieldValue reflect.Value, v reflect.Value) bool {
+	return convertUint(fieldValue, v) == nil
+}
+
+// convertUint converts any non-negative int/uint/integral float v that fits in the uint field fieldValue
+func convertUint(fieldValue reflect.Value, v reflect.Value) error {
+	switch fieldValue.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		// named types with their own unmarshaling method are unmarshaled like encoding/json does
+		if err := unmarshal(fieldValue, v); err != errNotHandled {
+			return err
 		}
-		// only set if underlying type is string
-		if v.Kind() == reflect.String {
-			t := time.Now()
-			// make sure date format is correct
-			if err := t.UnmarshalJSON([]byte(`"` + v.String() + `"`)); err == nil {
-				newValue := reflect.ValueOf(t)
-				fieldValue.Set(newValue)
-				return true
-			}
+		u, err := uintValue(v, fieldValue.Type().Bits())
+		if err != nil {
+			return err
 		}
-	case *time.Time:
-		if !v.IsValid() {
-			var t *time.Time
+		fieldValue.SetUint(u)
+		return nil
+	}
+
+	return errNotHandled
+}
+
+// convertNumber converts v to the int, uint, float, null.Int or null.Float field fieldValue like
+// the updaters of these types do, and returns why v was rejected. It returns errNotHandled for
+// the fields of other types.
+func convertNumber(fieldValue reflect.Value, v reflect.Value) error {
+	for _, convert := range []converter{convertNullInt, convertNullFloat, convertInt, convertUint, convertFloat} {
+		if err := convert(fieldValue, v); err != errNotHandled {
+			return err
+		}
+	}
+	return errNotHandled
+}
+
+// TimeUpdater update time
+func TimeUpdater(fieldValue reflect.Value, v reflect.Value) bool {
+	if _, ok := fieldValue.Interface().(time.Time); !ok || !v.IsValid() {
+		return false
+	}
+	// only set if underlying type is string
+	if v.Kind() == reflect.String {
+		t := time.Now()
+		// make sure date format is correct
+		if err := t.UnmarshalJSON([]byte(`"` + v.String() + `"`)); err == nil {
 			newValue := reflect.ValueOf(t)
 			fieldValue.Set(newValue)
 			return true
 		}
-		// only set if underlying type is string
-		if v.Kind() == reflect.String {
-			t := time.Now()
-			// make sure date format is correct
-			if err := t.UnmarshalJSON([]byte(`"` + v.String() + `"`)); err == nil {
-				newValue := reflect.ValueOf(&t)
-				fieldValue.Set(newValue)
-				return true
-			}
+	}
+
+	return false
+}
+
+// StructPointerUpdater update pointer to struct with null
+func StructPointerUpdater(fieldValue reflect.Value, v reflect.Value) bool {
+	// only process if field is pointer to any struct
+	if fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct {
+		if !v.IsValid() {
+			fieldValue.Set(reflect.Zero(fieldValue.Type()))
+			return true
 		}
 	}
 
 	return false
 }
 
+// elementUpdaters are the updaters of Updaters that convert the values MapStringInterfaceUpdater
+// and SQLNullUpdater hold when they are called on their own, which updateElement completes with them
+var elementUpdaters = []func(reflect.Value, reflect.Value) bool{
+	NullStringUpdater,
+	NullFloatUpdater,
+	NullIntUpdater,
+	NullBoolUpdater,
+	NullTimeUpdater,
+	IntUpdater,
+	UintUpdater,
+	FloatUpdater,
+	TimeUpdater,
+	BoolUpdater,
+	StructPointerUpdater,
+}
+
 // Updaters collection of all type updaters
 var Updaters = []func(reflect.Value, reflect.Value) bool{
 	NullStringUpdater,
@@ -405,9 +436,41 @@ var Updaters = []func(reflect.Value, reflect.Value) bool{
 	NullIntUpdater,
 	NullBoolUpdater,
 	NullTimeUpdater,
+	SQLNullUpdater,
 	MapStringInterfaceUpdater,
 	IntUpdater,
+	UintUpdater,
 	FloatUpdater,
 	TimeUpdater,
 	BoolUpdater,
+	StructPointerUpdater,
+}
+
+// DefaultRegistry is the registry of the updaters of Updaters by field type, used by Apply
+// and NewPatcher unless told otherwise (see WithRegistry and WithUpdaters)
+var DefaultRegistry = newDefaultRegistry()
+
+// newDefaultRegistry returns a registry of the updaters of Updaters
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(reflect.TypeOf(null.String{}), NullStringUpdater)
+	r.register(registryKey{fieldType: reflect.TypeOf(null.Float{}), anyValue: true}, convertNullFloat)
+	r.register(registryKey{fieldType: reflect.TypeOf(null.Int{}), anyValue: true}, convertNullInt)
+	r.Register(reflect.TypeOf(null.Bool{}), NullBoolUpdater)
+	r.Register(reflect.TypeOf(null.Time{}), NullTimeUpdater)
+	r.Register(reflect.TypeOf(time.Time{}), TimeUpdater)
+
+	for _, kind := range []reflect.Kind{reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64} {
+		r.register(registryKey{fieldKind: kind, anyValue: true}, convertInt)
+	}
+	for _, kind := range []reflect.Kind{reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr} {
+		r.register(registryKey{fieldKind: kind, anyValue: true}, convertUint)
+	}
+	for _, kind := range []reflect.Kind{reflect.Float32, reflect.Float64} {
+		r.register(registryKey{fieldKind: kind, anyValue: true}, convertFloat)
+	}
+	r.RegisterKind(reflect.Bool, BoolUpdater)
+	r.RegisterKind(reflect.Ptr, StructPointerUpdater)
+
+	return r
 }